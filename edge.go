@@ -7,6 +7,10 @@ type Edge struct {
 	Tags    []*Tag
 	KV      map[string]*EdgeKV
 	Content map[string]*EdgeContent
+	// Source and Target hold the endpoint nodes preloaded by EdgeQuery.WithSource
+	// and EdgeQuery.WithTarget.
+	Source *Node
+	Target *Node
 }
 
 // EdgeCore holds the core attributes of a directed edge between two nodes.
@@ -20,6 +24,9 @@ type EdgeCore struct {
 	Name        string    `gorm:"type:text;not null;index;default:'';index:idx_edge_source_kind_name,priority:3"`
 	Kind        string    `gorm:"type:text;not null;index;default:'';index:idx_edge_namespace_source_kind,priority:3;index:idx_edge_namespace_target_kind,priority:3;index:idx_edge_source_kind_name,priority:2"`
 	Status      string    `gorm:"type:text;not null;index;default:''"`
-	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"not null;autoUpdateTime"`
+	// UniquePair is set by SaveEdge for kinds whose schema allows a single edge
+	// between two nodes, and backs that rule with a unique index.
+	UniquePair *string   `gorm:"type:varchar(64);uniqueIndex:idx_edge_unique_pair"`
+	CreatedAt  time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"not null;autoUpdateTime"`
 }
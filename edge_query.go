@@ -1,11 +1,14 @@
 package nod
 
+import "gorm.io/gorm"
+
 type EdgeQuery struct {
-	repository   *Repository
-	where        Expression
-	fetchKV      bool
-	fetchContent bool
-	fetchTags    bool
+	repository *Repository
+	where      Expression
+	fetch      fetchOptions
+	source     *fetchOptions
+	target     *fetchOptions
+	page       pagination
 }
 
 func NewEdgeQuery(repository *Repository) *EdgeQuery {
@@ -15,17 +18,33 @@ func NewEdgeQuery(repository *Repository) *EdgeQuery {
 }
 
 func (q *EdgeQuery) WithKV() *EdgeQuery {
-	q.fetchKV = true
+	q.fetch.kv = true
 	return q
 }
 
 func (q *EdgeQuery) WithContent() *EdgeQuery {
-	q.fetchContent = true
+	q.fetch.content = true
 	return q
 }
 
 func (q *EdgeQuery) WithTags() *EdgeQuery {
-	q.fetchTags = true
+	q.fetch.tags = true
+	return q
+}
+
+// WithSource loads the source node of every matching edge. Options select the
+// relations loaded with the source nodes.
+func (q *EdgeQuery) WithSource(options ...LoadOption) *EdgeQuery {
+	fetch := loadOptions(options)
+	q.source = &fetch
+	return q
+}
+
+// WithTarget loads the target node of every matching edge. Options select the
+// relations loaded with the target nodes.
+func (q *EdgeQuery) WithTarget(options ...LoadOption) *EdgeQuery {
+	fetch := loadOptions(options)
+	q.target = &fetch
 	return q
 }
 
@@ -43,46 +62,186 @@ func (q *EdgeQuery) Where(expr Expression) *EdgeQuery {
 }
 
 func (q *EdgeQuery) FindAll() ([]*Edge, error) {
-	var cores []*EdgeCore
-	var kvs map[string][]*EdgeKV
-	var contents map[string][]*EdgeContent
-	var tags map[string][]*Tag
-	db := q.repository.db
+	cores, err := q.findCores(0)
+	if err != nil {
+		return nil, err
+	}
+	return q.load(cores)
+}
+
+// Count returns the number of matching edges. Order, limit, offset and
+// cursor are ignored.
+func (q *EdgeQuery) Count() (int64, error) {
+	return q.aggregation().count()
+}
+
+// Exists reports whether any edge matches the query.
+func (q *EdgeQuery) Exists() (bool, error) {
+	return q.aggregation().exists()
+}
+
+// Avg returns the average of a numeric KV field over the matching edges, or
+// nil when none of them has a value.
+func (q *EdgeQuery) Avg(field NumericField) (*float64, error) {
+	return aggregateValue[float64](q.aggregation(), "AVG", field.numericRef())
+}
+
+// GroupBy counts the matching edges per value of a core field, KV field or
+// content. Edges without the value are counted in a last group with a nil
+// value.
+func (q *EdgeQuery) GroupBy(field OrderField) ([]GroupCount, error) {
+	return q.aggregation().groupBy(field)
+}
 
+func (q *EdgeQuery) aggregation() aggregation {
+	return aggregation{repository: q.repository, where: q.where, scope: ScopeEdge}
+}
+
+// OrderBy sorts matching edges by a core field, timestamp, KV field or
+// content. Each call adds a sort key. NULL and missing values sort last in
+// either direction, and ties are broken by id.
+func (q *EdgeQuery) OrderBy(field OrderField, direction SortDirection) *EdgeQuery {
+	q.page.orderBy(field, direction)
+	return q
+}
+
+// Limit returns at most limit edges. Zero or less returns every edge.
+func (q *EdgeQuery) Limit(limit int) *EdgeQuery {
+	q.page.limit = limit
+	return q
+}
+
+// Offset skips the first offset matching edges.
+func (q *EdgeQuery) Offset(offset int) *EdgeQuery {
+	q.page.offset = offset
+	return q
+}
+
+// After continues the query behind the edge a cursor returned by FindPage
+// points at. The query has to use the sort keys of the query that returned
+// the cursor. A malformed cursor fails with InvalidCursorError, and a cursor
+// combined with Offset fails with OffsetWithCursorError.
+func (q *EdgeQuery) After(cursor string) *EdgeQuery {
+	q.page.after = cursor
+	return q
+}
+
+// FindPage returns at most Limit matching edges together with the cursor of
+// the next page, which is empty when no edge follows.
+func (q *EdgeQuery) FindPage() (*Page[Edge], error) {
+	page := &Page[Edge]{}
+	var cores []*EdgeCore
 	var err error
-	if q.where != nil {
-		db, err = applyExpression(db, q.where, ScopeEdge)
-		if err != nil {
-			return nil, err
-		}
+	if q.page.limit > 0 {
+		cores, page.NextCursor, err = q.findPageCores()
+	} else {
+		cores, err = q.findCores(0)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	err = db.Find(&cores).Error
+	page.Items, err = q.load(cores)
 	if err != nil {
 		return nil, err
 	}
+	return page, nil
+}
+
+func (q *EdgeQuery) findCores(limit int) ([]*EdgeCore, error) {
+	db, err := q.matching()
+	if err != nil {
+		return nil, err
+	}
+	db, err = q.page.apply(db, ScopeEdge, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var cores []*EdgeCore
+	if err := db.Find(&cores).Error; err != nil {
+		return nil, err
+	}
+	return cores, nil
+}
+
+// findPageCores returns the cores of a page together with the cursor of the
+// next page.
+func (q *EdgeQuery) findPageCores() ([]*EdgeCore, string, error) {
+	db, err := q.matching()
+	if err != nil {
+		return nil, "", err
+	}
+	ids, cursor, err := q.page.pageIds(db, ScopeEdge)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var cores []*EdgeCore
+	if err := q.repository.db.Where("id IN ?", ids).Find(&cores).Error; err != nil {
+		return nil, "", err
+	}
+	return orderByIds(cores, ids, func(core *EdgeCore) string { return core.Id }), cursor, nil
+}
 
-	nodeIds := make([]string, 0, len(cores))
+// matching returns the database handle restricted to the rows matching the
+// query's expression.
+func (q *EdgeQuery) matching() (*gorm.DB, error) {
+	if q.where == nil {
+		return q.repository.db, nil
+	}
+	return applyExpression(q.repository.db, q.where, ScopeEdge, q.repository)
+}
+
+// load wraps cores into edges with the relations selected by the query.
+func (q *EdgeQuery) load(cores []*EdgeCore) ([]*Edge, error) {
+	edges, err := q.repository.loadEdges(cores, q.fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.source != nil {
+		if err := q.repository.loadEdgeEndpoints(edges, false, *q.source); err != nil {
+			return nil, err
+		}
+	}
+	if q.target != nil {
+		if err := q.repository.loadEdgeEndpoints(edges, true, *q.target); err != nil {
+			return nil, err
+		}
+	}
+	return edges, nil
+}
+
+// loadEdges wraps cores into edges and loads the requested relations for all
+// of them in bulk.
+func (r *Repository) loadEdges(cores []*EdgeCore, fetch fetchOptions) ([]*Edge, error) {
+	var kvs map[string][]*EdgeKV
+	var contents map[string][]*EdgeContent
+	var tags map[string][]*Tag
+
+	edgeIds := make([]string, 0, len(cores))
 	for _, core := range cores {
-		nodeIds = append(nodeIds, core.Id)
+		edgeIds = append(edgeIds, core.Id)
 	}
 
-	if q.fetchKV {
-		kvs, err = q.repository.getEdgesKvs(nodeIds)
+	var err error
+	if fetch.kv {
+		kvs, err = r.getEdgesKvs(edgeIds)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if q.fetchContent {
-		contents, err = q.repository.getEdgesContents(nodeIds)
+	if fetch.content {
+		contents, err = r.getEdgesContents(edgeIds)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if q.fetchTags {
-		tags, err = q.repository.getEdgesTags(nodeIds)
+	if fetch.tags {
+		tags, err = r.getEdgesTags(edgeIds)
 		if err != nil {
 			return nil, err
 		}
@@ -94,21 +253,21 @@ func (q *EdgeQuery) FindAll() ([]*Edge, error) {
 			Core: *core,
 		}
 
-		if q.fetchKV {
+		if fetch.kv {
 			edge.KV = make(map[string]*EdgeKV)
 			for _, kv := range kvs[core.Id] {
 				edge.KV[kv.Key] = kv
 			}
 		}
 
-		if q.fetchContent {
+		if fetch.content {
 			edge.Content = make(map[string]*EdgeContent)
 			for _, content := range contents[core.Id] {
 				edge.Content[content.Key] = content
 			}
 		}
 
-		if q.fetchTags {
+		if fetch.tags {
 			edge.Tags = tags[core.Id]
 		}
 
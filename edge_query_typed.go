@@ -36,13 +36,77 @@ func (q *TypedEdgeQuery[T]) Where(expr Expression) *TypedEdgeQuery[T] {
 	return q
 }
 
+// OrderBy adds a sort key for matching edges.
+func (q *TypedEdgeQuery[T]) OrderBy(field OrderField, direction SortDirection) *TypedEdgeQuery[T] {
+	q.query.OrderBy(field, direction)
+	return q
+}
+
+// Limit returns at most limit edges.
+func (q *TypedEdgeQuery[T]) Limit(limit int) *TypedEdgeQuery[T] {
+	q.query.Limit(limit)
+	return q
+}
+
+// Offset skips the first offset matching edges.
+func (q *TypedEdgeQuery[T]) Offset(offset int) *TypedEdgeQuery[T] {
+	q.query.Offset(offset)
+	return q
+}
+
+// After continues the query behind the edge a cursor returned by FindPage points at.
+func (q *TypedEdgeQuery[T]) After(cursor string) *TypedEdgeQuery[T] {
+	q.query.After(cursor)
+	return q
+}
+
+// Count returns the number of matching edges.
+func (q *TypedEdgeQuery[T]) Count() (int64, error) {
+	return q.query.Count()
+}
+
+// Exists reports whether any edge matches the query.
+func (q *TypedEdgeQuery[T]) Exists() (bool, error) {
+	return q.query.Exists()
+}
+
+// Avg returns the average of a numeric KV field over the matching edges.
+func (q *TypedEdgeQuery[T]) Avg(field NumericField) (*float64, error) {
+	return q.query.Avg(field)
+}
+
+// GroupBy counts the matching edges per value of a field.
+func (q *TypedEdgeQuery[T]) GroupBy(field OrderField) ([]GroupCount, error) {
+	return q.query.GroupBy(field)
+}
+
+func (q *TypedEdgeQuery[T]) aggregation() aggregation {
+	return q.query.aggregation()
+}
+
 // FindAll returns all matching edges decoded into models of type T.
 func (q *TypedEdgeQuery[T]) FindAll() ([]*T, error) {
 	edges, err := q.query.FindAll()
 	if err != nil {
 		return nil, err
 	}
+	return q.decode(edges)
+}
+
+// FindPage returns a page of matching edges decoded into models of type T.
+func (q *TypedEdgeQuery[T]) FindPage() (*Page[T], error) {
+	page, err := q.query.FindPage()
+	if err != nil {
+		return nil, err
+	}
+	models, err := q.decode(page.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: models, NextCursor: page.NextCursor}, nil
+}
 
+func (q *TypedEdgeQuery[T]) decode(edges []*Edge) ([]*T, error) {
 	models := make([]*T, 0, len(edges))
 	for _, edge := range edges {
 		model, err := modelFromEdge[T](q.query.repository.adapters, edge)
@@ -0,0 +1,100 @@
+package nod
+
+import (
+	"slices"
+	"sync"
+)
+
+// EdgeSchema declares the rules SaveEdge enforces for edges of one kind.
+type EdgeSchema struct {
+	Kind string
+	// SourceKinds and TargetKinds list the node kinds allowed at each end of the
+	// edge. An empty list allows any node kind.
+	SourceKinds []string
+	TargetKinds []string
+	// Unique allows a single edge of the kind between the same source and target.
+	Unique bool
+	// MaxOutgoing and MaxIncoming limit the number of edges of the kind per
+	// source and per target node. Zero means no limit.
+	MaxOutgoing int
+	MaxIncoming int
+	// Symmetric makes edges of the kind undirected. Queries, traversals and
+	// preloads follow them from either endpoint, and a single edge is allowed
+	// between two nodes in either order. Cardinality limits then count the
+	// edges at either end of a node.
+	Symmetric bool
+}
+
+// EdgeSchemaRegistry stores edge schemas by edge kind.
+type EdgeSchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]EdgeSchema
+}
+
+// NewEdgeSchemaRegistry creates an empty EdgeSchemaRegistry.
+func NewEdgeSchemaRegistry() *EdgeSchemaRegistry {
+	return &EdgeSchemaRegistry{
+		schemas: make(map[string]EdgeSchema),
+	}
+}
+
+// Register adds the schema for its edge kind, replacing any schema registered
+// for that kind before.
+func (registry *EdgeSchemaRegistry) Register(schema EdgeSchema) error {
+	if registry == nil {
+		return NewEdgeSchemaRegistryIsNilError()
+	}
+	if schema.Kind == "" {
+		return NewEdgeSchemaKindIsEmptyError()
+	}
+	if schema.MaxOutgoing < 0 || schema.MaxIncoming < 0 {
+		return NewInvalidEdgeSchemaCardinalityError(schema.Kind)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.schemas == nil {
+		registry.schemas = make(map[string]EdgeSchema)
+	}
+	registry.schemas[schema.Kind] = schema
+	return nil
+}
+
+// Lookup returns the schema registered for the edge kind.
+func (registry *EdgeSchemaRegistry) Lookup(kind string) (EdgeSchema, bool) {
+	if registry == nil {
+		return EdgeSchema{}, false
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	schema, ok := registry.schemas[kind]
+	return schema, ok
+}
+
+// IsSymmetric reports whether the edge kind is registered as symmetric.
+func (registry *EdgeSchemaRegistry) IsSymmetric(kind string) bool {
+	schema, ok := registry.Lookup(kind)
+	return ok && schema.Symmetric
+}
+
+// symmetricKinds returns the sorted kinds registered as symmetric.
+func (registry *EdgeSchemaRegistry) symmetricKinds() []string {
+	if registry == nil {
+		return nil
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	var kinds []string
+	for kind, schema := range registry.schemas {
+		if schema.Symmetric {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	return kinds
+}
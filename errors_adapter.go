@@ -161,3 +161,15 @@ func NewAdapterOutputTypeMismatchError(expectedType, actualType string) *Adapter
 		ActualType:   actualType,
 	}
 }
+
+type ModelIdIsEmptyError struct {
+	TypeName string
+}
+
+func (e *ModelIdIsEmptyError) Error() string {
+	return "model has no id for type: " + e.TypeName
+}
+
+func NewModelIdIsEmptyError(typeName string) *ModelIdIsEmptyError {
+	return &ModelIdIsEmptyError{TypeName: typeName}
+}
@@ -0,0 +1,113 @@
+package nod
+
+import (
+	"strconv"
+	"strings"
+)
+
+type EdgeSchemaRegistryIsNilError struct {
+}
+
+func (e *EdgeSchemaRegistryIsNilError) Error() string {
+	return "edge schema registry is nil"
+}
+
+func NewEdgeSchemaRegistryIsNilError() *EdgeSchemaRegistryIsNilError {
+	return &EdgeSchemaRegistryIsNilError{}
+}
+
+type EdgeSchemaKindIsEmptyError struct {
+}
+
+func (e *EdgeSchemaKindIsEmptyError) Error() string {
+	return "edge schema kind is empty"
+}
+
+func NewEdgeSchemaKindIsEmptyError() *EdgeSchemaKindIsEmptyError {
+	return &EdgeSchemaKindIsEmptyError{}
+}
+
+type InvalidEdgeSchemaCardinalityError struct {
+	Kind string
+}
+
+func (e *InvalidEdgeSchemaCardinalityError) Error() string {
+	return "edge schema " + e.Kind + " has a negative cardinality"
+}
+
+func NewInvalidEdgeSchemaCardinalityError(kind string) *InvalidEdgeSchemaCardinalityError {
+	return &InvalidEdgeSchemaCardinalityError{Kind: kind}
+}
+
+// EdgeEndpointKindError reports an edge whose source or target node has a kind
+// the edge schema does not allow.
+type EdgeEndpointKindError struct {
+	EdgeKind     string
+	Endpoint     string
+	NodeId       string
+	NodeKind     string
+	AllowedKinds []string
+}
+
+func (e *EdgeEndpointKindError) Error() string {
+	return e.EdgeKind + " edge cannot have " + e.Endpoint + " " + e.NodeId + " of kind " + strconv.Quote(e.NodeKind) +
+		": allowed kinds are " + strings.Join(e.AllowedKinds, ", ")
+}
+
+func NewEdgeEndpointKindError(edgeKind, endpoint, nodeId, nodeKind string, allowedKinds []string) *EdgeEndpointKindError {
+	return &EdgeEndpointKindError{
+		EdgeKind:     edgeKind,
+		Endpoint:     endpoint,
+		NodeId:       nodeId,
+		NodeKind:     nodeKind,
+		AllowedKinds: allowedKinds,
+	}
+}
+
+// DuplicateEdgeError reports an edge that repeats the source, target and kind
+// of an existing edge whose schema is unique.
+type DuplicateEdgeError struct {
+	Kind       string
+	SourceId   string
+	TargetId   string
+	ExistingId string
+}
+
+func (e *DuplicateEdgeError) Error() string {
+	return e.Kind + " edge from " + e.SourceId + " to " + e.TargetId + " already exists: " + e.ExistingId
+}
+
+func NewDuplicateEdgeError(kind, sourceId, targetId, existingId string) *DuplicateEdgeError {
+	return &DuplicateEdgeError{
+		Kind:       kind,
+		SourceId:   sourceId,
+		TargetId:   targetId,
+		ExistingId: existingId,
+	}
+}
+
+// EdgeCardinalityError reports a node that already has the maximum number of
+// outgoing or incoming edges of a kind.
+type EdgeCardinalityError struct {
+	Kind      string
+	NodeId    string
+	Direction Direction
+	Max       int
+}
+
+func (e *EdgeCardinalityError) Error() string {
+	direction := "outgoing"
+	if e.Direction == DirectionIncoming {
+		direction = "incoming"
+	}
+	return "node " + e.NodeId + " already has " + strconv.Itoa(e.Max) + " " + direction + " " + e.Kind + " edges"
+}
+
+func NewEdgeCardinalityError(kind, nodeId string, direction Direction, maxEdges int) *EdgeCardinalityError {
+	return &EdgeCardinalityError{
+		Kind:      kind,
+		NodeId:    nodeId,
+		Direction: direction,
+		Max:       maxEdges,
+	}
+}
@@ -0,0 +1,75 @@
+package nod
+
+import "strconv"
+
+type UnsupportedDirectionError struct {
+	Direction Direction
+}
+
+func (e *UnsupportedDirectionError) Error() string {
+	return "unsupported direction: " + strconv.Itoa(int(e.Direction))
+}
+
+func NewUnsupportedDirectionError(direction Direction) *UnsupportedDirectionError {
+	return &UnsupportedDirectionError{Direction: direction}
+}
+
+type UnsupportedTraversalOrderError struct {
+	Order TraversalOrder
+}
+
+func (e *UnsupportedTraversalOrderError) Error() string {
+	return "unsupported traversal order: " + strconv.Itoa(int(e.Order))
+}
+
+func NewUnsupportedTraversalOrderError(order TraversalOrder) *UnsupportedTraversalOrderError {
+	return &UnsupportedTraversalOrderError{Order: order}
+}
+
+type NoPathError struct {
+	FromId string
+	ToId   string
+}
+
+func (e *NoPathError) Error() string {
+	return "no path from " + e.FromId + " to " + e.ToId
+}
+
+func NewNoPathError(fromId, toId string) *NoPathError {
+	return &NoPathError{
+		FromId: fromId,
+		ToId:   toId,
+	}
+}
+
+type NegativeEdgeWeightError struct {
+	EdgeId string
+	Weight float64
+}
+
+func (e *NegativeEdgeWeightError) Error() string {
+	return "edge " + e.EdgeId + " has negative weight " + strconv.FormatFloat(e.Weight, 'g', -1, 64)
+}
+
+func NewNegativeEdgeWeightError(edgeId string, weight float64) *NegativeEdgeWeightError {
+	return &NegativeEdgeWeightError{
+		EdgeId: edgeId,
+		Weight: weight,
+	}
+}
+
+type InvalidEdgeWeightError struct {
+	EdgeId string
+	Key    string
+}
+
+func (e *InvalidEdgeWeightError) Error() string {
+	return "edge " + e.EdgeId + " has a weight " + e.Key + " that is not a number"
+}
+
+func NewInvalidEdgeWeightError(edgeId, key string) *InvalidEdgeWeightError {
+	return &InvalidEdgeWeightError{
+		EdgeId: edgeId,
+		Key:    key,
+	}
+}
@@ -1,5 +1,7 @@
 package nod
 
+import "strconv"
+
 type NodeIsNilError struct {
 }
 
@@ -33,3 +35,114 @@ func (e *MultipleNodesFoundError) Error() string {
 func NewMultipleNodesFoundError() *MultipleNodesFoundError {
 	return &MultipleNodesFoundError{}
 }
+
+type ParentCycleError struct {
+	NodeId   string
+	ParentId string
+}
+
+func (e *ParentCycleError) Error() string {
+	return "node " + e.NodeId + " cannot be placed under " + e.ParentId + ": it would become its own ancestor"
+}
+
+func NewParentCycleError(nodeId, parentId string) *ParentCycleError {
+	return &ParentCycleError{
+		NodeId:   nodeId,
+		ParentId: parentId,
+	}
+}
+
+type NamespaceMismatchError struct {
+	NodeId    string
+	SiblingId string
+}
+
+func (e *NamespaceMismatchError) Error() string {
+	return "node " + e.NodeId + " cannot be placed next to " + e.SiblingId + ": they are in different namespaces"
+}
+
+func NewNamespaceMismatchError(nodeId, siblingId string) *NamespaceMismatchError {
+	return &NamespaceMismatchError{
+		NodeId:    nodeId,
+		SiblingId: siblingId,
+	}
+}
+
+type NodeHasChildrenError struct {
+	NodeId string
+}
+
+func (e *NodeHasChildrenError) Error() string {
+	return "node " + e.NodeId + " has children"
+}
+
+func NewNodeHasChildrenError(nodeId string) *NodeHasChildrenError {
+	return &NodeHasChildrenError{NodeId: nodeId}
+}
+
+type UnsupportedDeletePolicyError struct {
+	Policy DeletePolicy
+}
+
+func (e *UnsupportedDeletePolicyError) Error() string {
+	return "unsupported delete policy: " + strconv.Itoa(int(e.Policy))
+}
+
+func NewUnsupportedDeletePolicyError(policy DeletePolicy) *UnsupportedDeletePolicyError {
+	return &UnsupportedDeletePolicyError{Policy: policy}
+}
+
+type InvalidRankError struct {
+	Rank string
+}
+
+func (e *InvalidRankError) Error() string {
+	return "invalid node rank: " + e.Rank
+}
+
+func NewInvalidRankError(rank string) *InvalidRankError {
+	return &InvalidRankError{Rank: rank}
+}
+
+type DuplicateNodeNameError struct {
+	ParentId *string
+	Name     string
+}
+
+func (e *DuplicateNodeNameError) Error() string {
+	if e.ParentId == nil {
+		return "a root node named " + e.Name + " already exists"
+	}
+	return "node " + *e.ParentId + " already has a child named " + e.Name
+}
+
+func NewDuplicateNodeNameError(parentId *string, name string) *DuplicateNodeNameError {
+	return &DuplicateNodeNameError{
+		ParentId: parentId,
+		Name:     name,
+	}
+}
+
+type InvalidNodeNameError struct {
+	Name string
+}
+
+func (e *InvalidNodeNameError) Error() string {
+	return "node name " + e.Name + " contains the path separator " + pathSeparator
+}
+
+func NewInvalidNodeNameError(name string) *InvalidNodeNameError {
+	return &InvalidNodeNameError{Name: name}
+}
+
+type InvalidPathError struct {
+	Path string
+}
+
+func (e *InvalidPathError) Error() string {
+	return "invalid node path: " + e.Path
+}
+
+func NewInvalidPathError(path string) *InvalidPathError {
+	return &InvalidPathError{Path: path}
+}
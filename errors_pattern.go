@@ -0,0 +1,33 @@
+package nod
+
+import "strconv"
+
+type InvalidPatternError struct {
+	Reason string
+}
+
+func (e *InvalidPatternError) Error() string {
+	return "invalid pattern: " + e.Reason
+}
+
+func NewInvalidPatternError(reason string) *InvalidPatternError {
+	return &InvalidPatternError{Reason: reason}
+}
+
+type PatternSyntaxError struct {
+	Pattern string
+	Offset  int
+	Reason  string
+}
+
+func (e *PatternSyntaxError) Error() string {
+	return "pattern syntax error at offset " + strconv.Itoa(e.Offset) + ": " + e.Reason
+}
+
+func NewPatternSyntaxError(pattern string, offset int, reason string) *PatternSyntaxError {
+	return &PatternSyntaxError{
+		Pattern: pattern,
+		Offset:  offset,
+		Reason:  reason,
+	}
+}
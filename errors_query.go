@@ -35,3 +35,25 @@ func (e *UnsupportedScopeError) Error() string {
 func NewUnsupportedScopeError(scope Scope) *UnsupportedScopeError {
 	return &UnsupportedScopeError{Scope: scope}
 }
+
+type InvalidCursorError struct {
+	Reason string
+}
+
+func (e *InvalidCursorError) Error() string {
+	return "invalid cursor: " + e.Reason
+}
+
+func NewInvalidCursorError(reason string) *InvalidCursorError {
+	return &InvalidCursorError{Reason: reason}
+}
+
+type OffsetWithCursorError struct{}
+
+func (e *OffsetWithCursorError) Error() string {
+	return "offset cannot be combined with a cursor"
+}
+
+func NewOffsetWithCursorError() *OffsetWithCursorError {
+	return &OffsetWithCursorError{}
+}
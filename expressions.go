@@ -1,6 +1,9 @@
 package nod
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Scope uint8
 
@@ -30,12 +33,15 @@ const (
 	ValueTypeFloat
 	ValueTypeBool
 	ValueTypeTime
+	ValueTypeInt64
 )
 
 type FieldRef struct {
 	Source FiledSource
 	Type   ValueType
 	Name   string
+	// IgnoreCase compares the lowered field with lowered values.
+	IgnoreCase bool
 }
 
 type Operator uint8
@@ -49,6 +55,11 @@ const (
 	OperatorLessThanOrEqual
 	OperatorIn
 	OperatorNotIn
+	OperatorBetween
+	OperatorLike
+	OperatorExists
+	OperatorIsNull
+	OperatorIsNotNull
 )
 
 type comparisionExpression struct {
@@ -96,31 +107,11 @@ func coreStringField(name string) StringField {
 	}
 }
 
-func kvString(name string) StringField {
-	return StringField{
-		ref: FieldRef{
-			Source: SourceKV,
-			Type:   ValueTypeString,
-			Name:   name,
-		},
-	}
-}
-
-func kvInt(name string) StringField {
-	return StringField{
-		ref: FieldRef{
-			Source: SourceKV,
-			Type:   ValueTypeInt,
-			Name:   name,
-		},
-	}
-}
-
-func content(name string) StringField {
-	return StringField{
+func coreTimeField(name string) TimeField {
+	return TimeField{
 		ref: FieldRef{
-			Source: SourceContent,
-			Type:   ValueTypeString,
+			Source: SourceCore,
+			Type:   ValueTypeTime,
 			Name:   name,
 		},
 	}
@@ -160,16 +151,24 @@ var EdgeFields = struct {
 	NamespaceId StringField
 	SourceId    StringField
 	TargetId    StringField
+	Source      EndpointField
+	Target      EndpointField
 	Status      StringField
 	Kind        StringField
+	CreatedAt   TimeField
+	UpdatedAt   TimeField
 }{
 	Id:          coreStringField("id"),
 	Name:        coreStringField("name"),
 	NamespaceId: coreStringField("namespace_id"),
 	SourceId:    coreStringField("source_id"),
 	TargetId:    coreStringField("target_id"),
+	Source:      EndpointField{column: "source_id"},
+	Target:      EndpointField{column: "target_id"},
 	Status:      coreStringField("status"),
 	Kind:        coreStringField("kind"),
+	CreatedAt:   coreTimeField("created_at"),
+	UpdatedAt:   coreTimeField("updated_at"),
 }
 
 var NodeFields = struct {
@@ -177,15 +176,23 @@ var NodeFields = struct {
 	Name        StringField
 	NamespaceId StringField
 	ParentId    StringField
+	Parent      ParentField
+	Edges       EdgesField
 	Status      StringField
 	Kind        StringField
+	CreatedAt   TimeField
+	UpdatedAt   TimeField
 }{
 	Id:          coreStringField("id"),
 	Name:        coreStringField("name"),
 	NamespaceId: coreStringField("namespace_id"),
 	ParentId:    coreStringField("parent_id"),
+	Parent:      ParentField{},
+	Edges:       EdgesField{},
 	Status:      coreStringField("status"),
 	Kind:        coreStringField("kind"),
+	CreatedAt:   coreTimeField("created_at"),
+	UpdatedAt:   coreTimeField("updated_at"),
 }
 
 func KvString(name string) StringField {
@@ -243,6 +250,55 @@ func (f StringField) NotIn(value []string) Expression {
 	}
 }
 
+// IgnoreCase returns a copy of the field whose comparisons ignore case. Both
+// the field and the compared values are lowered with the database's LOWER.
+func (f StringField) IgnoreCase() StringField {
+	f.ref.IgnoreCase = true
+	return f
+}
+
+// Like matches the field against a LIKE pattern, where % matches any run of
+// characters and _ a single character. A backslash escapes the next
+// character. Matching is case-sensitive unless IgnoreCase is used.
+func (f StringField) Like(pattern string) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorLike,
+		Value:    pattern,
+	}
+}
+
+// Prefix matches fields starting with prefix. Wildcards in prefix match
+// literally.
+func (f StringField) Prefix(prefix string) Expression {
+	return f.Like(escapeLike(prefix) + "%")
+}
+
+// Suffix matches fields ending with suffix. Wildcards in suffix match
+// literally.
+func (f StringField) Suffix(suffix string) Expression {
+	return f.Like("%" + escapeLike(suffix))
+}
+
+// Contains matches fields containing substring. Wildcards in substring match
+// literally.
+func (f StringField) Contains(substring string) Expression {
+	return f.Like("%" + escapeLike(substring) + "%")
+}
+
+// escapeLike escapes the LIKE wildcards and the escape character itself, so
+// that s matches literally.
+func escapeLike(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c == '%' || c == '_' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
 func (f TimeField) Equals(value time.Time) Expression {
 	return &comparisionExpression{
 		Field:    f.ref,
@@ -251,6 +307,14 @@ func (f TimeField) Equals(value time.Time) Expression {
 	}
 }
 
+func (f TimeField) NotEquals(value time.Time) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorNotEqual,
+		Value:    value,
+	}
+}
+
 func (f TimeField) GreaterThan(value time.Time) Expression {
 	return &comparisionExpression{
 		Field:    f.ref,
@@ -282,3 +346,28 @@ func (f TimeField) LessThanOrEqual(value time.Time) Expression {
 		Value:    value,
 	}
 }
+
+// Between matches times from lower to upper, both included.
+func (f TimeField) Between(lower, upper time.Time) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorBetween,
+		Value:    []any{lower, upper},
+	}
+}
+
+func (f TimeField) In(values []time.Time) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorIn,
+		Value:    valuesToAny(values),
+	}
+}
+
+func (f TimeField) NotIn(values []time.Time) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorNotIn,
+		Value:    valuesToAny(values),
+	}
+}
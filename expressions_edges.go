@@ -0,0 +1,120 @@
+package nod
+
+type edgeRelationExpression struct {
+	Direction Direction
+	Kind      string
+	Node      Expression
+}
+
+type edgeCountExpression struct {
+	Edges    edgeRelationExpression
+	Operator Operator
+	Value    int64
+}
+
+type endpointExpression struct {
+	Column string
+	Node   Expression
+}
+
+func (*edgeRelationExpression) expression() {}
+func (*edgeCountExpression) expression()    {}
+func (*endpointExpression) expression()     {}
+
+// EdgesField builds expressions that match nodes by the edges they take part in.
+// An empty kind matches edges of any kind, and a nil node expression matches
+// any node at the other end of the edge.
+type EdgesField struct{}
+
+// Outgoing matches nodes with at least one edge of the given kind leading to a
+// node that satisfies targetExpr.
+func (EdgesField) Outgoing(kind string, targetExpr Expression) Expression {
+	return &edgeRelationExpression{
+		Direction: DirectionOutgoing,
+		Kind:      kind,
+		Node:      targetExpr,
+	}
+}
+
+// Incoming matches nodes with at least one edge of the given kind coming from a
+// node that satisfies sourceExpr.
+func (EdgesField) Incoming(kind string, sourceExpr Expression) Expression {
+	return &edgeRelationExpression{
+		Direction: DirectionIncoming,
+		Kind:      kind,
+		Node:      sourceExpr,
+	}
+}
+
+// OutgoingCount compares the number of edges of the given kind that lead to
+// nodes satisfying targetExpr.
+func (EdgesField) OutgoingCount(kind string, targetExpr Expression) EdgeCountField {
+	return EdgeCountField{edges: edgeRelationExpression{
+		Direction: DirectionOutgoing,
+		Kind:      kind,
+		Node:      targetExpr,
+	}}
+}
+
+// IncomingCount compares the number of edges of the given kind that come from
+// nodes satisfying sourceExpr.
+func (EdgesField) IncomingCount(kind string, sourceExpr Expression) EdgeCountField {
+	return EdgeCountField{edges: edgeRelationExpression{
+		Direction: DirectionIncoming,
+		Kind:      kind,
+		Node:      sourceExpr,
+	}}
+}
+
+// EdgeCountField compares the number of matching edges of a node.
+type EdgeCountField struct {
+	edges edgeRelationExpression
+}
+
+func (f EdgeCountField) compare(operator Operator, value int64) Expression {
+	return &edgeCountExpression{
+		Edges:    f.edges,
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+func (f EdgeCountField) Equals(value int64) Expression {
+	return f.compare(OperatorEqual, value)
+}
+
+func (f EdgeCountField) NotEquals(value int64) Expression {
+	return f.compare(OperatorNotEqual, value)
+}
+
+func (f EdgeCountField) GreaterThan(value int64) Expression {
+	return f.compare(OperatorGreaterThan, value)
+}
+
+func (f EdgeCountField) GreaterThanOrEqual(value int64) Expression {
+	return f.compare(OperatorGreaterThanOrEqual, value)
+}
+
+func (f EdgeCountField) LessThan(value int64) Expression {
+	return f.compare(OperatorLessThan, value)
+}
+
+func (f EdgeCountField) LessThanOrEqual(value int64) Expression {
+	return f.compare(OperatorLessThanOrEqual, value)
+}
+
+// EndpointField builds expressions that match edges by the node at one of
+// their ends.
+type EndpointField struct {
+	column string
+}
+
+// Matches matches edges whose endpoint node satisfies nodeExpr. Any node
+// expression can be used, including KV, content and tag conditions, and a nil
+// expression matches any node.
+func (f EndpointField) Matches(nodeExpr Expression) Expression {
+	return &endpointExpression{
+		Column: f.column,
+		Node:   nodeExpr,
+	}
+}
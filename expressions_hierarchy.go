@@ -0,0 +1,55 @@
+package nod
+
+type hierarchyRelation uint8
+
+const (
+	hierarchyDescendantOf hierarchyRelation = iota
+	hierarchyAncestorOf
+)
+
+type hierarchyExpression struct {
+	Relation hierarchyRelation
+	NodeId   string
+	MaxDepth int
+}
+
+type childMatchingExpression struct {
+	Expression Expression
+}
+
+func (*hierarchyExpression) expression()     {}
+func (*childMatchingExpression) expression() {}
+
+// ParentField builds expressions that match nodes by their position in the node hierarchy.
+type ParentField struct{}
+
+// ChildOf matches the direct children of the node with the given id.
+func (ParentField) ChildOf(id string) Expression {
+	return NodeFields.ParentId.Equals(id)
+}
+
+// DescendantOf matches every node below the node with the given id, down to
+// maxDepth levels. A maxDepth of zero or less matches the whole subtree.
+func (ParentField) DescendantOf(id string, maxDepth int) Expression {
+	return &hierarchyExpression{
+		Relation: hierarchyDescendantOf,
+		NodeId:   id,
+		MaxDepth: maxDepth,
+	}
+}
+
+// AncestorOf matches every node above the node with the given id.
+func (ParentField) AncestorOf(id string) Expression {
+	return &hierarchyExpression{
+		Relation: hierarchyAncestorOf,
+		NodeId:   id,
+	}
+}
+
+// HasChildMatching matches nodes with at least one direct child satisfying
+// expr. A nil expr matches nodes with any child.
+func (ParentField) HasChildMatching(expr Expression) Expression {
+	return &childMatchingExpression{
+		Expression: expr,
+	}
+}
@@ -0,0 +1,151 @@
+package nod
+
+// Number is the set of Go types stored in numeric KV columns.
+type Number interface {
+	int | int64 | float64
+}
+
+// NumberField references a numeric node or edge KV field. The KV column it
+// compares depends on T: ValueInt for int, ValueInt64 for int64 and
+// ValueNumber for float64.
+type NumberField[T Number] struct {
+	ref FieldRef
+}
+
+// BoolField references a boolean node or edge KV field.
+type BoolField struct {
+	ref FieldRef
+}
+
+func kvNumberField[T Number](name string, valueType ValueType) NumberField[T] {
+	return NumberField[T]{
+		ref: FieldRef{
+			Source: SourceKV,
+			Type:   valueType,
+			Name:   name,
+		},
+	}
+}
+
+// KvInt references an int node or edge KV field stored in ValueInt.
+func KvInt(name string) NumberField[int] {
+	return kvNumberField[int](name, ValueTypeInt)
+}
+
+// KvInt64 references an int64 node or edge KV field stored in ValueInt64.
+func KvInt64(name string) NumberField[int64] {
+	return kvNumberField[int64](name, ValueTypeInt64)
+}
+
+// KvFloat references a float64 node or edge KV field stored in ValueNumber.
+func KvFloat(name string) NumberField[float64] {
+	return kvNumberField[float64](name, ValueTypeFloat)
+}
+
+// KvBool references a boolean node or edge KV field stored in ValueBool.
+func KvBool(name string) BoolField {
+	return BoolField{
+		ref: FieldRef{
+			Source: SourceKV,
+			Type:   ValueTypeBool,
+			Name:   name,
+		},
+	}
+}
+
+func (f NumberField[T]) Equals(value T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorEqual,
+		Value:    value,
+	}
+}
+
+func (f NumberField[T]) NotEquals(value T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorNotEqual,
+		Value:    value,
+	}
+}
+
+func (f NumberField[T]) GreaterThan(value T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorGreaterThan,
+		Value:    value,
+	}
+}
+
+func (f NumberField[T]) GreaterThanOrEqual(value T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorGreaterThanOrEqual,
+		Value:    value,
+	}
+}
+
+func (f NumberField[T]) LessThan(value T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorLessThan,
+		Value:    value,
+	}
+}
+
+func (f NumberField[T]) LessThanOrEqual(value T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorLessThanOrEqual,
+		Value:    value,
+	}
+}
+
+// Between matches values from lower to upper, both included.
+func (f NumberField[T]) Between(lower, upper T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorBetween,
+		Value:    []any{lower, upper},
+	}
+}
+
+func (f NumberField[T]) In(values []T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorIn,
+		Value:    valuesToAny(values),
+	}
+}
+
+func (f NumberField[T]) NotIn(values []T) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorNotIn,
+		Value:    valuesToAny(values),
+	}
+}
+
+func (f BoolField) Equals(value bool) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorEqual,
+		Value:    value,
+	}
+}
+
+func (f BoolField) NotEquals(value bool) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorNotEqual,
+		Value:    value,
+	}
+}
+
+func (f BoolField) In(values []bool) Expression {
+	return &comparisionExpression{
+		Field:    f.ref,
+		Operator: OperatorIn,
+		Value:    valuesToAny(values),
+	}
+}
@@ -0,0 +1,89 @@
+package nod
+
+// presenceExpression builds a comparison that takes no value.
+func presenceExpression(ref FieldRef, operator Operator) Expression {
+	return &comparisionExpression{
+		Field:    ref,
+		Operator: operator,
+	}
+}
+
+// Exists matches rows that have the field. A KV or content field exists when
+// a value is stored under its key, whatever its type; a core field exists when
+// its column is not NULL.
+func (f StringField) Exists() Expression {
+	return presenceExpression(f.ref, OperatorExists)
+}
+
+// IsNull matches core fields whose column is NULL, such as the parent id of a
+// root node. For KV and content fields it matches a value stored under the key
+// whose column is NULL; use Not(Exists()) to match a missing key.
+func (f StringField) IsNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNull)
+}
+
+// IsNotNull matches fields whose column is not NULL.
+func (f StringField) IsNotNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNotNull)
+}
+
+// Exists matches rows with a KV value stored under the field's key, whatever
+// its type.
+func (f NumberField[T]) Exists() Expression {
+	return presenceExpression(f.ref, OperatorExists)
+}
+
+// IsNull matches KV values stored under the field's key without a value in
+// the field's column.
+func (f NumberField[T]) IsNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNull)
+}
+
+// IsNotNull matches KV values stored under the field's key with a value in
+// the field's column.
+func (f NumberField[T]) IsNotNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNotNull)
+}
+
+// Exists matches rows with a KV value stored under the field's key, whatever
+// its type.
+func (f BoolField) Exists() Expression {
+	return presenceExpression(f.ref, OperatorExists)
+}
+
+// IsNull matches KV values stored under the field's key without a value in
+// the ValueBool column.
+func (f BoolField) IsNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNull)
+}
+
+// IsNotNull matches KV values stored under the field's key with a value in
+// the ValueBool column.
+func (f BoolField) IsNotNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNotNull)
+}
+
+// Exists matches rows that have the field. A KV field exists when a value is
+// stored under its key, whatever its type; a timestamp field exists when its
+// column is not NULL.
+func (f TimeField) Exists() Expression {
+	return presenceExpression(f.ref, OperatorExists)
+}
+
+// IsNull matches timestamp fields whose column is NULL. For KV fields it
+// matches a value stored under the key without a value in the ValueTime
+// column; use Not(Exists()) to match a missing key.
+func (f TimeField) IsNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNull)
+}
+
+// IsNotNull matches timestamp fields whose column is not NULL, and KV values
+// stored under the field's key with a value in the ValueTime column.
+func (f TimeField) IsNotNull() Expression {
+	return presenceExpression(f.ref, OperatorIsNotNull)
+}
+
+// Any matches nodes or edges with at least one tag.
+func (f TagsField) Any() Expression {
+	return presenceExpression(FieldRef{Source: SourceTag, Type: ValueTypeString}, OperatorExists)
+}
@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/m87/nod"
+)
+
+// StronglyConnectedComponents groups nodes that can all reach each other
+// along edge directions. Every node belongs to exactly one component; nodes
+// that are not on a cycle form a component of their own. Ids within a
+// component, and components by their first id, are sorted.
+func (g *Graph) StronglyConnectedComponents() [][]string {
+	return sortComponents(g.tarjan(g.nodes, func(string) bool { return true }))
+}
+
+// WeaklyConnectedComponents groups nodes that are connected when edge
+// directions are ignored. Ids within a component, and components by their
+// first id, are sorted.
+func (g *Graph) WeaklyConnectedComponents() [][]string {
+	visited := make(map[string]bool, len(g.nodes))
+	var components [][]string
+	for _, root := range g.nodes {
+		if visited[root] {
+			continue
+		}
+		visited[root] = true
+		component := []string{root}
+		for i := 0; i < len(component); i++ {
+			id := component[i]
+			for _, neighbours := range [][]string{g.outgoing[id], g.incoming[id]} {
+				for _, next := range neighbours {
+					if !visited[next] {
+						visited[next] = true
+						component = append(component, next)
+					}
+				}
+			}
+		}
+		components = append(components, component)
+	}
+	return sortComponents(components)
+}
+
+// StronglyConnectedComponents loads the graph selected by opts and returns its
+// strongly connected components.
+func StronglyConnectedComponents(repository *nod.Repository, opts Options) ([][]string, error) {
+	g, err := Load(repository, opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.StronglyConnectedComponents(), nil
+}
+
+// WeaklyConnectedComponents loads the graph selected by opts and returns its
+// weakly connected components.
+func WeaklyConnectedComponents(repository *nod.Repository, opts Options) ([][]string, error) {
+	g, err := Load(repository, opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.WeaklyConnectedComponents(), nil
+}
+
+// tarjan returns the strongly connected components reachable from roots in
+// the subgraph of allowed nodes, in the order Tarjan's algorithm completes
+// them.
+func (g *Graph) tarjan(roots []string, allowed func(string) bool) [][]string {
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var components [][]string
+
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = len(index)
+		lowlink[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range g.outgoing[id] {
+			if !allowed(next) {
+				continue
+			}
+			if _, visited := index[next]; !visited {
+				connect(next)
+				lowlink[id] = min(lowlink[id], lowlink[next])
+			} else if onStack[next] {
+				lowlink[id] = min(lowlink[id], index[next])
+			}
+		}
+
+		if lowlink[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == id {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+
+	for _, root := range roots {
+		if _, visited := index[root]; !visited && allowed(root) {
+			connect(root)
+		}
+	}
+	return components
+}
+
+// sortComponents sorts the ids within each component and the components by
+// their first id.
+func sortComponents(components [][]string) [][]string {
+	for _, component := range components {
+		sort.Strings(component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
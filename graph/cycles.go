@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"slices"
+
+	"github.com/m87/nod"
+)
+
+// Cycles enumerates the elementary cycles of the graph with Johnson's
+// algorithm. Each cycle starts at its smallest node id and lists the nodes in
+// edge order; a self-loop is a cycle of one node. A limit above zero stops the
+// enumeration after that many cycles, as graphs can hold exponentially many.
+func (g *Graph) Cycles(limit int) [][]string {
+	position := make(map[string]int, len(g.nodes))
+	for i, id := range g.nodes {
+		position[id] = i
+	}
+
+	var cycles [][]string
+	done := func() bool {
+		return limit > 0 && len(cycles) >= limit
+	}
+
+	for i, start := range g.nodes {
+		if done() {
+			break
+		}
+
+		// Only cycles through start in the subgraph of nodes from start onwards
+		// are new; smaller nodes were handled in earlier rounds.
+		components := g.tarjan([]string{start}, func(id string) bool {
+			return position[id] >= i
+		})
+		component := map[string]bool{}
+		for _, id := range components[len(components)-1] {
+			component[id] = true
+		}
+
+		blocked := map[string]bool{}
+		blockedBy := map[string]map[string]bool{}
+		var unblock func(id string)
+		unblock = func(id string) {
+			blocked[id] = false
+			for other := range blockedBy[id] {
+				delete(blockedBy[id], other)
+				if blocked[other] {
+					unblock(other)
+				}
+			}
+		}
+
+		var path []string
+		var circuit func(id string) bool
+		circuit = func(id string) bool {
+			found := false
+			path = append(path, id)
+			blocked[id] = true
+
+			for _, next := range g.outgoing[id] {
+				if !component[next] || done() {
+					continue
+				}
+				if next == start {
+					cycles = append(cycles, slices.Clone(path))
+					found = true
+				} else if !blocked[next] && circuit(next) {
+					found = true
+				}
+			}
+
+			if found {
+				unblock(id)
+			} else {
+				for _, next := range g.outgoing[id] {
+					if !component[next] {
+						continue
+					}
+					if blockedBy[next] == nil {
+						blockedBy[next] = map[string]bool{}
+					}
+					blockedBy[next][id] = true
+				}
+			}
+			path = path[:len(path)-1]
+			return found
+		}
+		circuit(start)
+	}
+	return cycles
+}
+
+// Cycles loads the graph selected by opts and enumerates its elementary
+// cycles, up to limit when limit is above zero.
+func Cycles(repository *nod.Repository, opts Options, limit int) ([][]string, error) {
+	g, err := Load(repository, opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.Cycles(limit), nil
+}
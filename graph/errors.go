@@ -0,0 +1,15 @@
+package graph
+
+import "strings"
+
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "graph contains a cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+func NewCycleError(cycle []string) *CycleError {
+	return &CycleError{Cycle: cycle}
+}
@@ -0,0 +1,141 @@
+// Package graph provides graph algorithms over the nodes and edges stored in
+// a nod repository.
+package graph
+
+import (
+	"slices"
+
+	"github.com/m87/nod"
+)
+
+// DefaultBatchSize is the number of rows loaded per query when Options.BatchSize is not set.
+const DefaultBatchSize = 1000
+
+// Options selects the nodes and edges loaded into a Graph.
+type Options struct {
+	// NamespaceId restricts the graph to nodes of one namespace. When nil,
+	// nodes of every namespace are loaded.
+	NamespaceId *string
+	// NodeKinds restricts the graph to nodes of the given kinds.
+	NodeKinds []string
+	// EdgeKinds restricts the graph to edges of the given kinds.
+	EdgeKinds []string
+	// Reverse flips every edge. For dependency edges such as "depends_on" it
+	// makes the topological order list dependencies before their dependents.
+	Reverse bool
+	// BatchSize is the number of rows loaded per query.
+	BatchSize int
+}
+
+// Graph is an in-memory directed graph of node ids. Edges whose source or
+// target is not part of the graph are left out.
+type Graph struct {
+	nodes    []string
+	outgoing map[string][]string
+	incoming map[string][]string
+}
+
+type edgeRow struct {
+	Id       string
+	SourceId string
+	TargetId string
+	Kind     string
+}
+
+// Load reads the nodes and edges selected by opts from the repository. Rows
+// are read in id order, in batches of opts.BatchSize. Edges of kinds
+// registered as symmetric are added in both directions.
+func Load(repository *nod.Repository, opts Options) (*Graph, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	g := &Graph{
+		outgoing: map[string][]string{},
+		incoming: map[string][]string{},
+	}
+	members := map[string]bool{}
+
+	for last := ""; ; {
+		db := repository.DB().Model(&nod.NodeCore{}).Where("id > ?", last)
+		if opts.NamespaceId != nil {
+			db = db.Where("namespace_id = ?", *opts.NamespaceId)
+		}
+		if len(opts.NodeKinds) > 0 {
+			db = db.Where("kind IN ?", opts.NodeKinds)
+		}
+
+		var ids []string
+		if err := db.Order("id").Limit(batchSize).Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			members[id] = true
+		}
+		g.nodes = append(g.nodes, ids...)
+		if len(ids) < batchSize {
+			break
+		}
+		last = ids[len(ids)-1]
+	}
+
+	seen := map[[2]string]bool{}
+	add := func(source, target string) {
+		key := [2]string{source, target}
+		if !members[source] || !members[target] || seen[key] {
+			return
+		}
+		seen[key] = true
+		g.outgoing[source] = append(g.outgoing[source], target)
+		g.incoming[target] = append(g.incoming[target], source)
+	}
+	for last := ""; ; {
+		db := repository.DB().Model(&nod.EdgeCore{}).Select("id", "source_id", "target_id", "kind").Where("id > ?", last)
+		if len(opts.EdgeKinds) > 0 {
+			db = db.Where("kind IN ?", opts.EdgeKinds)
+		}
+
+		var rows []edgeRow
+		if err := db.Order("id").Limit(batchSize).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			source, target := row.SourceId, row.TargetId
+			if opts.Reverse {
+				source, target = target, source
+			}
+			add(source, target)
+			if repository.EdgeSchemas().IsSymmetric(row.Kind) {
+				add(target, source)
+			}
+		}
+		if len(rows) < batchSize {
+			break
+		}
+		last = rows[len(rows)-1].Id
+	}
+
+	for _, targets := range g.outgoing {
+		slices.Sort(targets)
+	}
+	for _, sources := range g.incoming {
+		slices.Sort(sources)
+	}
+	return g, nil
+}
+
+// Nodes returns the ids of all nodes in the graph in ascending order.
+func (g *Graph) Nodes() []string {
+	return slices.Clone(g.nodes)
+}
+
+// Successors returns the targets of the edges leaving the node with the given id.
+func (g *Graph) Successors(id string) []string {
+	return slices.Clone(g.outgoing[id])
+}
+
+// Predecessors returns the sources of the edges entering the node with the given id.
+func (g *Graph) Predecessors(id string) []string {
+	return slices.Clone(g.incoming[id])
+}
@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraph(nodes []string, edges [][2]string) *Graph {
+	g := &Graph{
+		nodes:    slices.Sorted(slices.Values(nodes)),
+		outgoing: map[string][]string{},
+		incoming: map[string][]string{},
+	}
+	for _, edge := range edges {
+		g.outgoing[edge[0]] = append(g.outgoing[edge[0]], edge[1])
+		g.incoming[edge[1]] = append(g.incoming[edge[1]], edge[0])
+	}
+	for _, targets := range g.outgoing {
+		slices.Sort(targets)
+	}
+	return g
+}
+
+func TestCycles(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"b", "c"}, {"c", "a"}, {"c", "c"}, {"c", "d"}},
+	)
+
+	require.Equal(t, [][]string{{"a", "b"}, {"a", "b", "c"}, {"c"}}, g.Cycles(0))
+	require.Len(t, g.Cycles(2), 2)
+}
+
+func TestCyclesCompleteGraph(t *testing.T) {
+	nodes := []string{"a", "b", "c", "d"}
+	var edges [][2]string
+	for _, source := range nodes {
+		for _, target := range nodes {
+			if source != target {
+				edges = append(edges, [2]string{source, target})
+			}
+		}
+	}
+
+	// A complete directed graph on four nodes has 6 cycles of length 2,
+	// 8 of length 3 and 6 of length 4.
+	require.Len(t, newTestGraph(nodes, edges).Cycles(0), 20)
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"b", "c"}, {"c", "d"}, {"d", "c"}},
+	)
+
+	require.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, g.StronglyConnectedComponents())
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"d", "b"}, {"c", "a"}, {"b", "a"}},
+	)
+
+	order, err := g.TopologicalSort()
+	require.NoError(t, err)
+	require.Equal(t, []string{"c", "d", "b", "a"}, order)
+
+	g = newTestGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}})
+	_, err = g.TopologicalSort()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Equal(t, []string{"b", "c"}, cycleErr.Cycle)
+}
@@ -0,0 +1,30 @@
+package graph
+
+import "github.com/m87/nod"
+
+// Resolve decodes the nodes with the given ids into models of type T, keeping
+// the order of ids.
+func Resolve[T any](repository *nod.Repository, ids []string) ([]*T, error) {
+	return nod.Nodes[T](repository).GetNodes(ids)
+}
+
+// ResolveAll decodes groups of node ids, such as connected components, into
+// groups of models of type T with a single lookup.
+func ResolveAll[T any](repository *nod.Repository, groups [][]string) ([][]*T, error) {
+	var ids []string
+	for _, group := range groups {
+		ids = append(ids, group...)
+	}
+
+	models, err := Resolve[T](repository, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]*T, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, models[:len(group):len(group)])
+		models = models[len(group):]
+	}
+	return result, nil
+}
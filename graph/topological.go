@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"container/heap"
+
+	"github.com/m87/nod"
+)
+
+// TopologicalSort orders the nodes so that every edge leads from an earlier
+// node to a later one. Among nodes that could come next, the smallest id is
+// taken first, which keeps the order stable. A graph with a cycle fails with
+// CycleError holding one of the cycles.
+func (g *Graph) TopologicalSort() ([]string, error) {
+	inDegree := make(map[string]int, len(g.nodes))
+	for _, id := range g.nodes {
+		inDegree[id] = len(g.incoming[id])
+	}
+
+	ready := &idQueue{}
+	for _, id := range g.nodes {
+		if inDegree[id] == 0 {
+			heap.Push(ready, id)
+		}
+	}
+
+	order := make([]string, 0, len(g.nodes))
+	for ready.Len() > 0 {
+		id := heap.Pop(ready).(string)
+		order = append(order, id)
+		for _, next := range g.outgoing[id] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+
+	if len(order) < len(g.nodes) {
+		return nil, NewCycleError(g.Cycles(1)[0])
+	}
+	return order, nil
+}
+
+// TopologicalSort loads the graph selected by opts and sorts it topologically.
+func TopologicalSort(repository *nod.Repository, opts Options) ([]string, error) {
+	g, err := Load(repository, opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.TopologicalSort()
+}
+
+// idQueue is a min-heap of node ids.
+type idQueue []string
+
+func (q idQueue) Len() int           { return len(q) }
+func (q idQueue) Less(i, j int) bool { return q[i] < q[j] }
+func (q idQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *idQueue) Push(item any) { *q = append(*q, item.(string)) }
+
+func (q *idQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
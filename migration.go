@@ -13,7 +13,7 @@ const (
 	schemaVersionPropertyKey = "version"
 
 	// CurrentSchemaVersion is the schema version managed by nod migrations.
-	CurrentSchemaVersion = 3
+	CurrentSchemaVersion = 4
 )
 
 // Property stores nod's internal schema properties.
@@ -29,7 +29,8 @@ func (Property) TableName() string {
 }
 
 // Migrate migrates nod tables and records the applied nod schema version.
-func Migrate(db *gorm.DB) error {
+// Options enable optional parts of the schema.
+func Migrate(db *gorm.DB, options ...MigrateOption) error {
 	version, hasVersion, err := readSchemaVersion(db)
 	if err != nil {
 		return err
@@ -47,6 +48,10 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	if err := applyMigrateOptions(db, options); err != nil {
+		return err
+	}
+
 	if !hasVersion || version < CurrentSchemaVersion {
 		return writeSchemaVersion(db, CurrentSchemaVersion)
 	}
@@ -0,0 +1,106 @@
+package nod
+
+import "gorm.io/gorm"
+
+const uniqueSiblingNamesPropertyKey = "unique_sibling_names"
+
+// MigrateOption enables an optional part of the nod schema. Enabled options are
+// recorded in the schema properties and stay enabled for later migrations.
+type MigrateOption func(*migrateOptions)
+
+type migrateOptions struct {
+	uniqueSiblingNames bool
+	closureTable       bool
+}
+
+// WithUniqueSiblingNames requires node names to be unique among siblings in
+// the same namespace and keeps the path separator out of node names, which
+// makes path lookups unambiguous. Migration fails when existing siblings
+// already share a name or an existing name contains the separator.
+func WithUniqueSiblingNames() MigrateOption {
+	return func(options *migrateOptions) {
+		options.uniqueSiblingNames = true
+	}
+}
+
+// WithClosureTable installs the node_closures table, which indexes every
+// ancestor of every node. Once installed, nod keeps it in sync and answers
+// subtree and ancestor lookups with indexed joins instead of recursive queries.
+func WithClosureTable() MigrateOption {
+	return func(options *migrateOptions) {
+		options.closureTable = true
+	}
+}
+
+func applyMigrateOptions(db *gorm.DB, options []MigrateOption) error {
+	var enabled migrateOptions
+	for _, option := range options {
+		option(&enabled)
+	}
+
+	if enabled.uniqueSiblingNames {
+		if err := ensureNoSeparatorInNames(db); err != nil {
+			return err
+		}
+		if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS "idx_node_sibling_name" ON "node_cores" (COALESCE("namespace_id", ''), COALESCE("parent_id", ''), "name")`).Error; err != nil {
+			return err
+		}
+		if err := writeFlagProperty(db, uniqueSiblingNamesPropertyKey); err != nil {
+			return err
+		}
+	}
+
+	if enabled.closureTable {
+		if err := migrateClosureTable(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureNoSeparatorInNames fails with InvalidNodeNameError when a stored node
+// name contains the path separator.
+func ensureNoSeparatorInNames(db *gorm.DB) error {
+	var names []string
+	err := db.Model(&NodeCore{}).Where("name LIKE ?", "%"+pathSeparator+"%").Limit(1).Pluck("name", &names).Error
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return NewInvalidNodeNameError(names[0])
+	}
+	return nil
+}
+
+// migrateClosureTable creates the closure table and fills it the first time
+// it is enabled. Later migrations keep the maintained rows.
+func migrateClosureTable(db *gorm.DB) error {
+	if err := db.AutoMigrate(&NodeClosure{}); err != nil {
+		return err
+	}
+
+	enabled, err := readFlagProperty(db, nodeClosurePropertyKey)
+	if err != nil || enabled {
+		return err
+	}
+	if err := RebuildNodeClosure(db); err != nil {
+		return err
+	}
+	return writeFlagProperty(db, nodeClosurePropertyKey)
+}
+
+func readFlagProperty(db *gorm.DB, key string) (bool, error) {
+	var count int64
+	err := db.Model(&Property{}).Where("key = ? AND value = ?", key, "true").Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func writeFlagProperty(db *gorm.DB, key string) error {
+	return db.Save(&Property{
+		Key:   key,
+		Value: "true",
+	}).Error
+}
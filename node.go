@@ -8,17 +8,23 @@ type Node struct {
 	Tags    []*Tag
 	KV      map[string]*NodeKV
 	Content map[string]*NodeContent
+	// Outgoing and Incoming hold the edges preloaded by NodeQuery.WithOutgoingEdges
+	// and NodeQuery.WithIncomingEdges.
+	Outgoing []*Edge
+	Incoming []*Edge
 }
 
 // NodeCore holds the core attributes of a node stored in the database.
 type NodeCore struct {
 	Id          string    `gorm:"type:varchar(36);primaryKey"`
 	NamespaceId *string   `gorm:"type:varchar(36);index:idx_namespace_id,priority:1;index"`
-	ParentId    *string   `gorm:"type:varchar(36);index:idx_parent_id,priority:2;index"`
+	ParentId    *string   `gorm:"type:varchar(36);index:idx_parent_id,priority:2;index;index:idx_parent_rank,priority:1;index:idx_parent_name,priority:1"`
 	Parent      *NodeCore `gorm:"foreignKey:ParentId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	Kind        string    `gorm:"type:text;not null;index;default:''"`
-	Status      string    `gorm:"type:text;not null;index;default:''"`
-	Name        string    `gorm:"type:text;not null;index"`
-	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"not null;autoUpdateTime"`
+	// Rank orders the node among its siblings. nod assigns it when it is left empty.
+	Rank      string    `gorm:"type:text;not null;default:'';index:idx_parent_rank,priority:2"`
+	Kind      string    `gorm:"type:text;not null;index;default:''"`
+	Status    string    `gorm:"type:text;not null;index;default:''"`
+	Name      string    `gorm:"type:text;not null;index;index:idx_parent_name,priority:2"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime"`
 }
@@ -0,0 +1,17 @@
+package nod
+
+// CloneOptions adjusts the copies made by NodeScope.CloneSubtree.
+type CloneOptions struct {
+	// NamespaceId moves every copied node and edge into the given namespace.
+	// When nil, copies stay in the namespace of their originals.
+	NamespaceId *string
+	// Name renames the copy of the subtree root. When empty, the root keeps its name.
+	Name string
+}
+
+// CloneResult maps the ids of copied nodes and edges to the ids of their copies.
+type CloneResult struct {
+	RootId  string
+	NodeIds map[string]string
+	EdgeIds map[string]string
+}
@@ -0,0 +1,106 @@
+package nod
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const nodeClosurePropertyKey = "node_closure"
+
+// NodeClosure links a node to each of its ancestors and to itself. The table is
+// optional and maintained by nod once it is enabled with WithClosureTable.
+type NodeClosure struct {
+	AncestorId   string    `gorm:"type:varchar(36);primaryKey;index:idx_node_closure_ancestor_depth,priority:1"`
+	Ancestor     *NodeCore `gorm:"foreignKey:AncestorId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	DescendantId string    `gorm:"type:varchar(36);primaryKey;index:idx_node_closure_descendant_depth,priority:1"`
+	Descendant   *NodeCore `gorm:"foreignKey:DescendantId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// Depth is the distance between both nodes; every node is its own ancestor at depth 0.
+	Depth int `gorm:"not null;index:idx_node_closure_ancestor_depth,priority:2;index:idx_node_closure_descendant_depth,priority:2"`
+}
+
+// rebuildClosureSQL fills node_closures from the parent references.
+const rebuildClosureSQL = `INSERT INTO "node_closures" ("ancestor_id", "descendant_id", "depth") ` +
+	`WITH RECURSIVE "closure" ("ancestor_id", "descendant_id", "depth") AS (` +
+	`SELECT "id", "id", 0 FROM "node_cores" ` +
+	`UNION ALL ` +
+	`SELECT "closure"."ancestor_id", "node_cores"."id", "closure"."depth" + 1 FROM "node_cores" ` +
+	`JOIN "closure" ON "node_cores"."parent_id" = "closure"."descendant_id" ` +
+	`WHERE "closure"."depth" < ?) ` +
+	`SELECT "ancestor_id", "descendant_id", MIN("depth") FROM "closure" GROUP BY "ancestor_id", "descendant_id"`
+
+// detachClosureSQL removes the links between the subtree of a node and the
+// ancestors outside of that subtree.
+const detachClosureSQL = `DELETE FROM "node_closures" ` +
+	`WHERE "descendant_id" IN (SELECT "descendant_id" FROM "node_closures" WHERE "ancestor_id" = ?) ` +
+	`AND "ancestor_id" NOT IN (SELECT "descendant_id" FROM "node_closures" WHERE "ancestor_id" = ?)`
+
+// attachClosureSQL links every node in the subtree of a node to the new parent
+// and to all ancestors of the new parent.
+const attachClosureSQL = `INSERT INTO "node_closures" ("ancestor_id", "descendant_id", "depth") ` +
+	`SELECT "parents"."ancestor_id", "subtree"."descendant_id", "parents"."depth" + "subtree"."depth" + 1 ` +
+	`FROM "node_closures" AS "parents" ` +
+	`JOIN "node_closures" AS "subtree" ON "subtree"."ancestor_id" = ? ` +
+	`WHERE "parents"."descendant_id" = ?`
+
+const selectClosureDescendants = `SELECT "node_cores".*, "node_closures"."depth" FROM "node_cores" ` +
+	`JOIN "node_closures" ON "node_closures"."descendant_id" = "node_cores"."id" ` +
+	`WHERE "node_closures"."ancestor_id" = ? AND "node_closures"."depth" BETWEEN 1 AND ? ` +
+	`ORDER BY "node_closures"."depth", "node_cores"."rank", "node_cores"."name", "node_cores"."id"`
+
+const selectClosureAncestorNames = `SELECT "node_cores"."name" FROM "node_cores" ` +
+	`JOIN "node_closures" ON "node_closures"."ancestor_id" = "node_cores"."id" ` +
+	`WHERE "node_closures"."descendant_id" = ? AND "node_closures"."depth" > 0 ` +
+	`ORDER BY "node_closures"."depth" DESC`
+
+const selectClosureAncestors = `SELECT "node_cores".*, "node_closures"."depth" FROM "node_cores" ` +
+	`JOIN "node_closures" ON "node_closures"."ancestor_id" = "node_cores"."id" ` +
+	`WHERE "node_closures"."descendant_id" = ? AND "node_closures"."depth" > 0 ` +
+	`ORDER BY "node_closures"."depth", "node_cores"."rank", "node_cores"."name", "node_cores"."id"`
+
+// RebuildNodeClosure recreates the closure table from the parent references of
+// all nodes. Use it to repair the table after nodes were changed without nod.
+func RebuildNodeClosure(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM "node_closures"`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(rebuildClosureSQL, hierarchyDepthLimit).Error
+	})
+}
+
+// syncNodeClosure links the node with the given id, and its subtree, below
+// parentId. Nothing is rewritten while the node keeps its parent or the
+// closure table is not installed.
+func syncNodeClosure(tx *gorm.DB, features schemaFeatures, id string, parentId *string) error {
+	if !features.closure {
+		return nil
+	}
+
+	self := &NodeClosure{AncestorId: id, DescendantId: id}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(self).Error; err != nil {
+		return err
+	}
+
+	var current NodeClosure
+	err := tx.Select("ancestor_id").Take(&current, "descendant_id = ? AND depth = 1", id).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if parentId == nil {
+			return nil
+		}
+	case err != nil:
+		return err
+	case parentId != nil && *parentId == current.AncestorId:
+		return nil
+	}
+
+	if err := tx.Exec(detachClosureSQL, id, id).Error; err != nil {
+		return err
+	}
+	if parentId == nil {
+		return nil
+	}
+	return tx.Exec(attachClosureSQL, id, *parentId).Error
+}
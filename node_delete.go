@@ -0,0 +1,22 @@
+package nod
+
+// DeletePolicy decides what happens to the children of deleted nodes.
+type DeletePolicy uint8
+
+const (
+	// DeletePolicyOrphan turns the children of deleted nodes into root nodes.
+	DeletePolicyOrphan DeletePolicy = iota
+	// DeletePolicyCascade deletes the whole subtree below deleted nodes.
+	DeletePolicyCascade
+	// DeletePolicyReparent moves the children of deleted nodes to their nearest surviving ancestor.
+	DeletePolicyReparent
+	// DeletePolicyRestrict refuses to delete nodes that still have children.
+	DeletePolicyRestrict
+)
+
+// DeleteResult reports how many rows a node deletion removed. Edges counts the
+// edges removed together with their source or target node.
+type DeleteResult struct {
+	Nodes int64
+	Edges int64
+}
@@ -4,11 +4,19 @@ import "gorm.io/gorm"
 
 // NodeQuery represents a query for nodes in the repository, allowing for filtering based on various criteria.
 type NodeQuery struct {
-	repository   *Repository
-	where        Expression
-	fetchKV      bool
-	fetchContent bool
-	fetchTags    bool
+	repository *Repository
+	where      Expression
+	fetch      fetchOptions
+	outgoing   *edgePreload
+	incoming   *edgePreload
+	page       pagination
+}
+
+// fetchOptions selects the relations loaded alongside node or edge cores.
+type fetchOptions struct {
+	kv      bool
+	content bool
+	tags    bool
 }
 
 // NewNodeQuery creates a new NodeQuery for the given repository.
@@ -19,17 +27,33 @@ func NewNodeQuery(repository *Repository) *NodeQuery {
 }
 
 func (q *NodeQuery) WithKV() *NodeQuery {
-	q.fetchKV = true
+	q.fetch.kv = true
 	return q
 }
 
 func (q *NodeQuery) WithContent() *NodeQuery {
-	q.fetchContent = true
+	q.fetch.content = true
 	return q
 }
 
 func (q *NodeQuery) WithTags() *NodeQuery {
-	q.fetchTags = true
+	q.fetch.tags = true
+	return q
+}
+
+// WithOutgoingEdges loads the outgoing edges of the given kind, or of every
+// kind when kind is empty, into Node.Outgoing. Options select the relations
+// loaded with the edges.
+func (q *NodeQuery) WithOutgoingEdges(kind string, options ...LoadOption) *NodeQuery {
+	q.outgoing = q.outgoing.add(kind, options)
+	return q
+}
+
+// WithIncomingEdges loads the incoming edges of the given kind, or of every
+// kind when kind is empty, into Node.Incoming. Options select the relations
+// loaded with the edges.
+func (q *NodeQuery) WithIncomingEdges(kind string, options ...LoadOption) *NodeQuery {
+	q.incoming = q.incoming.add(kind, options)
 	return q
 }
 
@@ -57,6 +81,18 @@ func Or(exprs ...Expression) Expression {
 	}
 }
 
+// Not negates an expression. Rows where the expression is unknown because a
+// column is NULL match the negation, and KV, tag and content conditions turn
+// into NOT EXISTS, so Not(Tags().Has("x")) also matches rows without any tags.
+func Not(expr Expression) Expression {
+	if expr == nil {
+		return nil
+	}
+	return &notExpression{
+		Expression: expr,
+	}
+}
+
 func (q *NodeQuery) Where(expr Expression) *NodeQuery {
 	if expr == nil {
 		return q
@@ -87,67 +123,227 @@ func (q *NodeQuery) FindFirst() (*Node, error) {
 	return nodes[0], nil
 }
 
+// Count returns the number of matching nodes. Order, limit, offset and
+// cursor are ignored.
+func (q *NodeQuery) Count() (int64, error) {
+	return q.aggregation().count()
+}
+
+// Exists reports whether any node matches the query.
+func (q *NodeQuery) Exists() (bool, error) {
+	return q.aggregation().exists()
+}
+
+// Avg returns the average of a numeric KV field over the matching nodes, or
+// nil when none of them has a value.
+func (q *NodeQuery) Avg(field NumericField) (*float64, error) {
+	return aggregateValue[float64](q.aggregation(), "AVG", field.numericRef())
+}
+
+// GroupBy counts the matching nodes per value of a core field, KV field or
+// content. Nodes without the value are counted in a last group with a nil
+// value.
+func (q *NodeQuery) GroupBy(field OrderField) ([]GroupCount, error) {
+	return q.aggregation().groupBy(field)
+}
+
+func (q *NodeQuery) aggregation() aggregation {
+	return aggregation{repository: q.repository, where: q.where, scope: ScopeNode}
+}
+
+// OrderBy sorts matching nodes by a core field, timestamp, KV field or
+// content. Each call adds a sort key. NULL and missing values sort last in
+// either direction, and ties are broken by id.
+func (q *NodeQuery) OrderBy(field OrderField, direction SortDirection) *NodeQuery {
+	q.page.orderBy(field, direction)
+	return q
+}
+
+// Limit returns at most limit nodes. Zero or less returns every node.
+func (q *NodeQuery) Limit(limit int) *NodeQuery {
+	q.page.limit = limit
+	return q
+}
+
+// Offset skips the first offset matching nodes.
+func (q *NodeQuery) Offset(offset int) *NodeQuery {
+	q.page.offset = offset
+	return q
+}
+
+// After continues the query behind the node a cursor returned by FindPage
+// points at. The query has to use the sort keys of the query that returned
+// the cursor. A malformed cursor fails with InvalidCursorError, and a cursor
+// combined with Offset fails with OffsetWithCursorError.
+func (q *NodeQuery) After(cursor string) *NodeQuery {
+	q.page.after = cursor
+	return q
+}
+
+// FindPage returns at most Limit matching nodes together with the cursor of
+// the next page, which is empty when no node follows.
+func (q *NodeQuery) FindPage() (*Page[Node], error) {
+	page := &Page[Node]{}
+	var cores []*NodeCore
+	var err error
+	if q.page.limit > 0 {
+		cores, page.NextCursor, err = q.findPageCores()
+	} else {
+		cores, err = q.findCores(0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page.Items, err = q.load(cores)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
 // DeleteAll deletes every node matching the query. An empty query is rejected
-// to prevent accidental deletion of all nodes.
+// to prevent accidental deletion of all nodes. Children of deleted nodes become
+// root nodes.
 func (q *NodeQuery) DeleteAll() error {
+	_, err := q.DeleteAllWithPolicy(DeletePolicyOrphan)
+	return err
+}
+
+// DeleteAllWithPolicy deletes every node matching the query, applies policy to
+// their children and reports how many nodes and edges were removed.
+func (q *NodeQuery) DeleteAllWithPolicy(policy DeletePolicy) (*DeleteResult, error) {
 	if q.where == nil {
-		return gorm.ErrMissingWhereClause
+		return nil, gorm.ErrMissingWhereClause
 	}
 
-	return q.repository.Transaction(func(txRepository *Repository) error {
-		db, err := applyExpression(txRepository.db, q.where, ScopeNode)
+	var result *DeleteResult
+	err := q.repository.Transaction(func(txRepository *Repository) error {
+		db, err := applyExpression(txRepository.db.Model(&NodeCore{}), q.where, ScopeNode, txRepository)
 		if err != nil {
 			return err
 		}
-		return db.Delete(&NodeCore{}).Error
+
+		var ids []string
+		if err := db.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		features, err := txRepository.enabledFeatures()
+		if err != nil {
+			return err
+		}
+		result, err = deleteNodes(txRepository.db, features, ids, policy)
+		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (q *NodeQuery) find(limit int) ([]*Node, error) {
+	cores, err := q.findCores(limit)
+	if err != nil {
+		return nil, err
+	}
+	return q.load(cores)
+}
+
+func (q *NodeQuery) findCores(limit int) ([]*NodeCore, error) {
+	db, err := q.matching()
+	if err != nil {
+		return nil, err
+	}
+	db, err = q.page.apply(db, ScopeNode, limit)
+	if err != nil {
+		return nil, err
+	}
+
 	var cores []*NodeCore
-	var kv map[string][]*NodeKV
-	var contents map[string][]*NodeContent
-	var tags map[string][]*Tag
+	if err := db.Find(&cores).Error; err != nil {
+		return nil, err
+	}
+	return cores, nil
+}
+
+// findPageCores returns the cores of a page together with the cursor of the
+// next page.
+func (q *NodeQuery) findPageCores() ([]*NodeCore, string, error) {
+	db, err := q.matching()
+	if err != nil {
+		return nil, "", err
+	}
+	ids, cursor, err := q.page.pageIds(db, ScopeNode)
+	if err != nil {
+		return nil, "", err
+	}
 
-	db := q.repository.db
+	var cores []*NodeCore
+	if err := q.repository.db.Where("id IN ?", ids).Find(&cores).Error; err != nil {
+		return nil, "", err
+	}
+	return orderByIds(cores, ids, func(core *NodeCore) string { return core.Id }), cursor, nil
+}
 
-	var err error
-	if q.where != nil {
-		db, err = applyExpression(db, q.where, ScopeNode)
-		if err != nil {
+// matching returns the database handle restricted to the rows matching the
+// query's expression.
+func (q *NodeQuery) matching() (*gorm.DB, error) {
+	if q.where == nil {
+		return q.repository.db, nil
+	}
+	return applyExpression(q.repository.db, q.where, ScopeNode, q.repository)
+}
+
+// load wraps cores into nodes with the relations selected by the query.
+func (q *NodeQuery) load(cores []*NodeCore) ([]*Node, error) {
+	nodes, err := q.repository.loadNodes(cores, q.fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.outgoing != nil {
+		if err := q.repository.loadNodeEdges(nodes, DirectionOutgoing, q.outgoing); err != nil {
 			return nil, err
 		}
 	}
-	if limit > 0 {
-		db = db.Limit(limit)
+	if q.incoming != nil {
+		if err := q.repository.loadNodeEdges(nodes, DirectionIncoming, q.incoming); err != nil {
+			return nil, err
+		}
 	}
+	return nodes, nil
+}
 
-	result := db.Find(&cores)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+// loadNodes wraps cores into nodes and loads the requested relations for all
+// of them in bulk.
+func (r *Repository) loadNodes(cores []*NodeCore, fetch fetchOptions) ([]*Node, error) {
+	var kv map[string][]*NodeKV
+	var contents map[string][]*NodeContent
+	var tags map[string][]*Tag
 
 	nodeIds := make([]string, 0, len(cores))
 	for _, core := range cores {
 		nodeIds = append(nodeIds, core.Id)
 	}
 
-	if q.fetchKV {
-		kv, err = q.repository.getNodesKvs(nodeIds)
+	var err error
+	if fetch.kv {
+		kv, err = r.getNodesKvs(nodeIds)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if q.fetchContent {
-		contents, err = q.repository.getNodesContents(nodeIds)
+	if fetch.content {
+		contents, err = r.getNodesContents(nodeIds)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if q.fetchTags {
-		tags, err = q.repository.getNodesTags(nodeIds)
+	if fetch.tags {
+		tags, err = r.getNodesTags(nodeIds)
 		if err != nil {
 			return nil, err
 		}
@@ -159,21 +355,21 @@ func (q *NodeQuery) find(limit int) ([]*Node, error) {
 			Core: *core,
 		}
 
-		if q.fetchKV {
+		if fetch.kv {
 			node.KV = make(map[string]*NodeKV)
 			for _, kv := range kv[core.Id] {
 				node.KV[kv.Key] = kv
 			}
 		}
 
-		if q.fetchContent {
+		if fetch.content {
 			node.Content = make(map[string]*NodeContent)
 			for _, content := range contents[core.Id] {
 				node.Content[content.Key] = content
 			}
 		}
 
-		if q.fetchTags {
+		if fetch.tags {
 			node.Tags = tags[core.Id]
 		}
 
@@ -183,8 +379,12 @@ func (q *NodeQuery) find(limit int) ([]*Node, error) {
 	return nodes, nil
 }
 
-func applyExpression(db *gorm.DB, expr Expression, scope Scope) (*gorm.DB, error) {
-	compiler := queryCompiler{db: db, scope: scope}
+func applyExpression(db *gorm.DB, expr Expression, scope Scope, repository *Repository) (*gorm.DB, error) {
+	features, err := repository.enabledFeatures()
+	if err != nil {
+		return nil, err
+	}
+	compiler := queryCompiler{db: db, scope: scope, schemas: repository.edgeSchemas, features: features}
 	clauseExpr, err := compiler.compile(expr)
 	if err != nil {
 		return nil, err
@@ -36,13 +36,77 @@ func (q *TypedNodeQuery[T]) Where(expr Expression) *TypedNodeQuery[T] {
 	return q
 }
 
+// OrderBy adds a sort key for matching nodes.
+func (q *TypedNodeQuery[T]) OrderBy(field OrderField, direction SortDirection) *TypedNodeQuery[T] {
+	q.query.OrderBy(field, direction)
+	return q
+}
+
+// Limit returns at most limit nodes.
+func (q *TypedNodeQuery[T]) Limit(limit int) *TypedNodeQuery[T] {
+	q.query.Limit(limit)
+	return q
+}
+
+// Offset skips the first offset matching nodes.
+func (q *TypedNodeQuery[T]) Offset(offset int) *TypedNodeQuery[T] {
+	q.query.Offset(offset)
+	return q
+}
+
+// After continues the query behind the node a cursor returned by FindPage points at.
+func (q *TypedNodeQuery[T]) After(cursor string) *TypedNodeQuery[T] {
+	q.query.After(cursor)
+	return q
+}
+
+// Count returns the number of matching nodes.
+func (q *TypedNodeQuery[T]) Count() (int64, error) {
+	return q.query.Count()
+}
+
+// Exists reports whether any node matches the query.
+func (q *TypedNodeQuery[T]) Exists() (bool, error) {
+	return q.query.Exists()
+}
+
+// Avg returns the average of a numeric KV field over the matching nodes.
+func (q *TypedNodeQuery[T]) Avg(field NumericField) (*float64, error) {
+	return q.query.Avg(field)
+}
+
+// GroupBy counts the matching nodes per value of a field.
+func (q *TypedNodeQuery[T]) GroupBy(field OrderField) ([]GroupCount, error) {
+	return q.query.GroupBy(field)
+}
+
+func (q *TypedNodeQuery[T]) aggregation() aggregation {
+	return q.query.aggregation()
+}
+
 // FindAll returns all matching nodes decoded into models of type T.
 func (q *TypedNodeQuery[T]) FindAll() ([]*T, error) {
 	nodes, err := q.query.FindAll()
 	if err != nil {
 		return nil, err
 	}
+	return q.decode(nodes)
+}
+
+// FindPage returns a page of matching nodes decoded into models of type T.
+func (q *TypedNodeQuery[T]) FindPage() (*Page[T], error) {
+	page, err := q.query.FindPage()
+	if err != nil {
+		return nil, err
+	}
+	models, err := q.decode(page.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: models, NextCursor: page.NextCursor}, nil
+}
 
+func (q *TypedNodeQuery[T]) decode(nodes []*Node) ([]*T, error) {
 	models := make([]*T, 0, len(nodes))
 	for _, node := range nodes {
 		model, err := modelFromNode[T](q.query.repository.adapters, node)
@@ -69,3 +133,8 @@ func (q *TypedNodeQuery[T]) FindFirst() (*T, error) {
 func (q *TypedNodeQuery[T]) DeleteAll() error {
 	return q.query.DeleteAll()
 }
+
+// DeleteAllWithPolicy deletes every node matching the query and applies policy to their children.
+func (q *TypedNodeQuery[T]) DeleteAllWithPolicy(policy DeletePolicy) (*DeleteResult, error) {
+	return q.query.DeleteAllWithPolicy(policy)
+}
@@ -0,0 +1,25 @@
+package nod
+
+// PathOptions restricts the edges a path search may follow.
+type PathOptions struct {
+	// Direction selects which edges are followed. The zero value follows
+	// outgoing edges.
+	Direction Direction
+	// Kinds restricts the search to edges of the given kinds.
+	Kinds []string
+	// Where restricts the search to edges matching the edge expression.
+	Where Expression
+	// WeightKey names a numeric edge KV used as the edge weight. When empty,
+	// every edge weighs 1 and the path with the fewest edges is returned.
+	// Edges without the KV weigh 1 as well, while a KV that holds no number
+	// fails the search with InvalidEdgeWeightError.
+	WeightKey string
+}
+
+// Path is an ordered chain of nodes and the edges between them. Edges[i]
+// connects Nodes[i] and Nodes[i+1].
+type Path struct {
+	Nodes []*Node
+	Edges []*Edge
+	Cost  float64
+}
@@ -0,0 +1,88 @@
+package nod
+
+// PatternQuery matches a path pattern of nodes joined by edges, such as a task
+// that blocks another task which is assigned to a person. Build it with Node
+// and Edge, alternating and starting and ending with a node, or parse it with
+// ParsePattern, then run it with FindAll.
+type PatternQuery struct {
+	repository *Repository
+	elements   []patternElement
+	where      []patternWhere
+	fetch      fetchOptions
+	limit      int
+}
+
+type patternElement struct {
+	alias     string
+	edge      bool
+	direction Direction
+	where     Expression
+}
+
+type patternWhere struct {
+	alias string
+	expr  Expression
+}
+
+// PatternMatch binds the named nodes and edges of a pattern to one match.
+type PatternMatch struct {
+	Nodes map[string]*Node
+	Edges map[string]*Edge
+}
+
+// NewPatternQuery creates an empty pattern query.
+func NewPatternQuery(repository *Repository) *PatternQuery {
+	return &PatternQuery{
+		repository: repository,
+	}
+}
+
+// Node appends a node matching the node expression, which may be nil. Nodes
+// with an alias are returned in PatternMatch.Nodes, and a repeated alias binds
+// the same node again.
+func (q *PatternQuery) Node(alias string, where Expression) *PatternQuery {
+	q.elements = append(q.elements, patternElement{alias: alias, where: where})
+	return q
+}
+
+// Edge appends an edge matching the edge expression, which may be nil, between
+// the previous and the next node. DirectionOutgoing leads from the previous
+// node to the next one, DirectionIncoming the other way round and
+// DirectionBoth either way. Edges with an alias are returned in
+// PatternMatch.Edges.
+func (q *PatternQuery) Edge(alias string, direction Direction, where Expression) *PatternQuery {
+	q.elements = append(q.elements, patternElement{alias: alias, edge: true, direction: direction, where: where})
+	return q
+}
+
+// Where adds an expression that the node or edge with the given alias must
+// satisfy.
+func (q *PatternQuery) Where(alias string, expr Expression) *PatternQuery {
+	q.where = append(q.where, patternWhere{alias: alias, expr: expr})
+	return q
+}
+
+// WithKV loads the KV values of matched nodes and edges.
+func (q *PatternQuery) WithKV() *PatternQuery {
+	q.fetch.kv = true
+	return q
+}
+
+// WithContent loads the content of matched nodes and edges.
+func (q *PatternQuery) WithContent() *PatternQuery {
+	q.fetch.content = true
+	return q
+}
+
+// WithTags loads the tags of matched nodes and edges.
+func (q *PatternQuery) WithTags() *PatternQuery {
+	q.fetch.tags = true
+	return q
+}
+
+// Limit returns at most the given number of matches. Zero or less returns all
+// matches.
+func (q *PatternQuery) Limit(limit int) *PatternQuery {
+	q.limit = limit
+	return q
+}
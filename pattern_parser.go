@@ -0,0 +1,279 @@
+package nod
+
+import (
+	"strconv"
+	"strings"
+)
+
+// patternNodeFields and patternEdgeFields are the core fields a pattern
+// property can constrain. Other property keys constrain KV values.
+var (
+	patternNodeFields = map[string]StringField{
+		"id":           NodeFields.Id,
+		"name":         NodeFields.Name,
+		"namespace_id": NodeFields.NamespaceId,
+		"parent_id":    NodeFields.ParentId,
+		"status":       NodeFields.Status,
+		"kind":         NodeFields.Kind,
+	}
+	patternEdgeFields = map[string]StringField{
+		"id":           EdgeFields.Id,
+		"name":         EdgeFields.Name,
+		"namespace_id": EdgeFields.NamespaceId,
+		"source_id":    EdgeFields.SourceId,
+		"target_id":    EdgeFields.TargetId,
+		"status":       EdgeFields.Status,
+		"kind":         EdgeFields.Kind,
+	}
+)
+
+// ParsePattern parses a textual path pattern into a PatternQuery, for example
+//
+//	(a:task {status: 'open'})-[:blocks]->(b:task)<-[:assigned_to]-(p:person)
+//
+// A node is written (alias:kind {key: value, ...}) and an edge -[alias:kind
+// {key: value, ...}]-> or <-[...]-, or -[...]- for either direction. Alias,
+// kind and properties are all optional, and -->, <-- and -- are edges without
+// any of them. Property keys name core fields, which take quoted strings, or
+// KV keys, which take quoted strings, numbers or booleans. An integer matches
+// the int, int64 and float KV columns. Further constraints can be added with
+// Where.
+func ParsePattern(repository *Repository, pattern string) (*PatternQuery, error) {
+	parser := &patternParser{input: pattern}
+	query := NewPatternQuery(repository)
+
+	alias, where, err := parser.node()
+	if err != nil {
+		return nil, err
+	}
+	query.Node(alias, where)
+
+	for parser.skipSpace(); parser.pos < len(parser.input); parser.skipSpace() {
+		alias, direction, where, err := parser.edge()
+		if err != nil {
+			return nil, err
+		}
+		query.Edge(alias, direction, where)
+
+		alias, where, err = parser.node()
+		if err != nil {
+			return nil, err
+		}
+		query.Node(alias, where)
+	}
+	return query, nil
+}
+
+type patternParser struct {
+	input string
+	pos   int
+}
+
+func (p *patternParser) fail(reason string) error {
+	return NewPatternSyntaxError(p.input, p.pos, reason)
+}
+
+func (p *patternParser) skipSpace() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips whitespace and the token when the input continues with it.
+func (p *patternParser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *patternParser) expect(token string) error {
+	if !p.consume(token) {
+		return p.fail("expected " + strconv.Quote(token))
+	}
+	return nil
+}
+
+// identifier reads a possibly empty name of letters, digits and underscores.
+func (p *patternParser) identifier() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *patternParser) node() (string, Expression, error) {
+	if err := p.expect("("); err != nil {
+		return "", nil, err
+	}
+	alias, where, err := p.element(patternNodeFields)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return "", nil, err
+	}
+	return alias, where, nil
+}
+
+func (p *patternParser) edge() (string, Direction, Expression, error) {
+	p.skipSpace()
+	start := p.pos
+	incoming := p.consume("<")
+	if err := p.expect("-"); err != nil {
+		return "", 0, nil, err
+	}
+
+	var alias string
+	var where Expression
+	if p.consume("[") {
+		var err error
+		alias, where, err = p.element(patternEdgeFields)
+		if err != nil {
+			return "", 0, nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", 0, nil, err
+		}
+	}
+
+	if err := p.expect("-"); err != nil {
+		return "", 0, nil, err
+	}
+	outgoing := p.consume(">")
+
+	switch {
+	case incoming && outgoing:
+		p.pos = start
+		return "", 0, nil, p.fail("an edge cannot point in both directions")
+	case incoming:
+		return alias, DirectionIncoming, where, nil
+	case outgoing:
+		return alias, DirectionOutgoing, where, nil
+	default:
+		return alias, DirectionBoth, where, nil
+	}
+}
+
+// element reads the alias, kind and properties of a node or an edge.
+func (p *patternParser) element(fields map[string]StringField) (string, Expression, error) {
+	alias := p.identifier()
+
+	var exprs []Expression
+	if p.consume(":") {
+		kind := p.identifier()
+		if kind == "" {
+			return "", nil, p.fail("expected a kind")
+		}
+		exprs = append(exprs, fields["kind"].Equals(kind))
+	}
+
+	if p.consume("{") {
+		for first := true; !p.consume("}"); first = false {
+			if !first {
+				if err := p.expect(","); err != nil {
+					return "", nil, err
+				}
+			}
+			expr, err := p.property(fields)
+			if err != nil {
+				return "", nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+	}
+
+	switch len(exprs) {
+	case 0:
+		return alias, nil, nil
+	case 1:
+		return alias, exprs[0], nil
+	default:
+		return alias, And(exprs...), nil
+	}
+}
+
+func (p *patternParser) property(fields map[string]StringField) (Expression, error) {
+	key := p.identifier()
+	if key == "" {
+		return nil, p.fail("expected a property key")
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"') {
+		value, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if field, ok := fields[key]; ok {
+			return field.Equals(value), nil
+		}
+		return KvString(key).Equals(value), nil
+	}
+
+	start := p.pos
+	if _, ok := fields[key]; ok {
+		return nil, p.fail("property " + key + " needs a quoted string")
+	}
+	if p.consume("true") {
+		return KvBool(key).Equals(true), nil
+	}
+	if p.consume("false") {
+		return KvBool(key).Equals(false), nil
+	}
+
+	p.consume("-")
+	for p.pos < len(p.input) && strings.ContainsRune("0123456789.", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	literal := p.input[start:p.pos]
+	if value, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return Or(
+			KvInt(key).Equals(int(value)),
+			KvInt64(key).Equals(value),
+			KvFloat(key).Equals(float64(value)),
+		), nil
+	}
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.fail("expected a quoted string, a number or a boolean")
+	}
+	return KvFloat(key).Equals(value), nil
+}
+
+// quoted reads a string in single or double quotes. A backslash escapes the
+// next character.
+func (p *patternParser) quoted() (string, error) {
+	start := p.pos
+	quote := p.input[p.pos]
+	p.pos++
+
+	var value strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return value.String(), nil
+		case c == '\\' && p.pos < len(p.input):
+			value.WriteByte(p.input[p.pos])
+			p.pos++
+		default:
+			value.WriteByte(c)
+		}
+	}
+	p.pos = start
+	return "", p.fail("unterminated string")
+}
@@ -0,0 +1,57 @@
+package nod
+
+// LoadOption selects the relations loaded with preloaded endpoint nodes or
+// neighbouring edges.
+type LoadOption func(*fetchOptions)
+
+// LoadKV loads key-value attributes of preloaded nodes or edges.
+func LoadKV() LoadOption {
+	return func(fetch *fetchOptions) {
+		fetch.kv = true
+	}
+}
+
+// LoadContent loads content of preloaded nodes or edges.
+func LoadContent() LoadOption {
+	return func(fetch *fetchOptions) {
+		fetch.content = true
+	}
+}
+
+// LoadTags loads tags of preloaded nodes or edges.
+func LoadTags() LoadOption {
+	return func(fetch *fetchOptions) {
+		fetch.tags = true
+	}
+}
+
+func loadOptions(options []LoadOption) fetchOptions {
+	var fetch fetchOptions
+	for _, option := range options {
+		option(&fetch)
+	}
+	return fetch
+}
+
+// edgePreload describes the edges of one direction preloaded with nodes.
+type edgePreload struct {
+	allKinds bool
+	kinds    []string
+	fetch    fetchOptions
+}
+
+// add includes edges of the given kind, or of every kind when kind is empty.
+func (p *edgePreload) add(kind string, options []LoadOption) *edgePreload {
+	if p == nil {
+		p = &edgePreload{}
+	}
+	if kind == "" {
+		p.allKinds = true
+	} else {
+		p.kinds = append(p.kinds, kind)
+	}
+	for _, option := range options {
+		option(&p.fetch)
+	}
+	return p
+}
@@ -0,0 +1,209 @@
+package nod
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NumericField is a numeric KV field that can be aggregated.
+type NumericField interface {
+	numericRef() FieldRef
+}
+
+func (f NumberField[T]) numericRef() FieldRef { return f.ref }
+
+// AggregateQuery is a node or edge query, typed or not, whose matching rows
+// Sum, Min and Max aggregate.
+type AggregateQuery interface {
+	aggregation() aggregation
+}
+
+// Sum returns the sum of a numeric KV field over the nodes or edges matching
+// query, or 0 when none of them has a value. The sum has the type of the
+// field, so integer fields are summed without rounding. Sum, Min and Max are
+// functions because methods cannot have type parameters.
+func Sum[T Number](query AggregateQuery, field NumberField[T]) (T, error) {
+	sum, err := aggregateValue[T](query.aggregation(), "SUM", field.ref)
+	if err != nil || sum == nil {
+		var zero T
+		return zero, err
+	}
+	return *sum, nil
+}
+
+// Min returns the lowest value of a numeric KV field among the nodes or edges
+// matching query, or nil when none of them has a value.
+func Min[T Number](query AggregateQuery, field NumberField[T]) (*T, error) {
+	return aggregateValue[T](query.aggregation(), "MIN", field.ref)
+}
+
+// Max returns the highest value of a numeric KV field among the nodes or
+// edges matching query, or nil when none of them has a value.
+func Max[T Number](query AggregateQuery, field NumberField[T]) (*T, error) {
+	return aggregateValue[T](query.aggregation(), "MAX", field.ref)
+}
+
+// GroupCount is the number of nodes or edges sharing a value. Value is nil for
+// the group without a value, and otherwise has the type of the field: string,
+// int64, float64, bool or time.Time.
+type GroupCount struct {
+	Value any
+	Count int64
+}
+
+// aggregation computes counts and aggregates over the nodes or edges matching
+// an expression. Order, limit, offset and cursor of a query do not apply.
+type aggregation struct {
+	repository *Repository
+	where      Expression
+	scope      Scope
+}
+
+// matching returns the cores table restricted to matching rows.
+func (a aggregation) matching() (*gorm.DB, string, error) {
+	prefix, err := scopePrefix(a.scope)
+	if err != nil {
+		return nil, "", err
+	}
+	db := a.repository.db.Session(&gorm.Session{NewDB: true}).Table(prefix + "cores")
+	if a.where != nil {
+		db, err = applyExpression(db, a.where, a.scope, a.repository)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return db, prefix, nil
+}
+
+func (a aggregation) count() (int64, error) {
+	db, _, err := a.matching()
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (a aggregation) exists() (bool, error) {
+	db, prefix, err := a.matching()
+	if err != nil {
+		return false, err
+	}
+	var ids []string
+	if err := db.Limit(1).Pluck(prefix+"cores.id", &ids).Error; err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+// aggregateValue applies the SQL aggregate function to the values of a
+// numeric KV field of the matching rows and scans the result into V. It
+// returns nil when no matching row has a value.
+func aggregateValue[V any](a aggregation, function string, ref FieldRef) (*V, error) {
+	column, err := kvColumnName(ref.Type)
+	if err != nil {
+		return nil, err
+	}
+	matching, prefix, err := a.matching()
+	if err != nil {
+		return nil, err
+	}
+
+	table := prefix + "kvs"
+	var value *V
+	err = a.repository.db.Session(&gorm.Session{NewDB: true}).
+		Table(table).
+		Select(function+"(?)", clause.Column{Table: table, Name: column}).
+		Where(clause.Eq{Column: clause.Column{Table: table, Name: "key"}, Value: ref.Name}).
+		Where(clause.Expr{SQL: "? IN (?)", Vars: []interface{}{
+			clause.Column{Table: table, Name: prefix + "id"},
+			matching.Select(prefix + "cores.id"),
+		}}).
+		Row().
+		Scan(&value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// groupBy counts the matching rows per value of a field. Groups are ordered
+// by value, with the group without a value last.
+func (a aggregation) groupBy(field OrderField) ([]GroupCount, error) {
+	ref := field.orderRef()
+	db, prefix, err := a.matching()
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch ref.Source {
+	case SourceCore:
+		value = clause.Column{Table: prefix + "cores", Name: ref.Name}
+	case SourceKV, SourceContent:
+		table, column, err := sortTable(prefix, ref)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Joins("LEFT JOIN ? ON ? = ? AND ? = ?",
+			clause.Table{Name: table},
+			clause.Column{Table: table, Name: prefix + "id"},
+			clause.Column{Table: prefix + "cores", Name: "id"},
+			clause.Column{Table: table, Name: "key"},
+			ref.Name,
+		)
+		value = clause.Column{Table: table, Name: column}
+	default:
+		return nil, fmt.Errorf("unsupported group field source: %v", ref.Source)
+	}
+	valueType := ref.Type
+	if ref.IgnoreCase {
+		value = lower(value)
+		valueType = ValueTypeString
+	}
+
+	db = db.Select("? AS group_value, COUNT(*) AS group_count", value).
+		Group("group_value").
+		Order("CASE WHEN group_value IS NULL THEN 1 ELSE 0 END, group_value")
+
+	switch valueType {
+	case ValueTypeString:
+		return scanGroups[string](db)
+	case ValueTypeInt, ValueTypeInt64:
+		return scanGroups[int64](db)
+	case ValueTypeFloat:
+		return scanGroups[float64](db)
+	case ValueTypeBool:
+		return scanGroups[bool](db)
+	case ValueTypeTime:
+		return scanGroups[time.Time](db)
+	default:
+		return nil, fmt.Errorf("unsupported group field type: %v", valueType)
+	}
+}
+
+func scanGroups[T any](db *gorm.DB) ([]GroupCount, error) {
+	var rows []struct {
+		GroupValue *T
+		GroupCount int64
+	}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	groups := make([]GroupCount, 0, len(rows))
+	for _, row := range rows {
+		group := GroupCount{Count: row.GroupCount}
+		if row.GroupValue != nil {
+			group.Value = *row.GroupValue
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
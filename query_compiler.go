@@ -2,14 +2,17 @@ package nod
 
 import (
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type queryCompiler struct {
-	db    *gorm.DB
-	scope Scope
+	db       *gorm.DB
+	scope    Scope
+	schemas  *EdgeSchemaRegistry
+	features schemaFeatures
 }
 
 func (c queryCompiler) compile(expr Expression) (clause.Expression, error) {
@@ -23,6 +26,18 @@ func (c queryCompiler) compile(expr Expression) (clause.Expression, error) {
 		return c.compileAnd(expr)
 	case *orExpression:
 		return c.compileOr(expr)
+	case *notExpression:
+		return c.compileNot(expr)
+	case *hierarchyExpression:
+		return c.compileHierarchy(expr)
+	case *childMatchingExpression:
+		return c.compileChildMatching(expr)
+	case *edgeRelationExpression:
+		return c.compileEdgeRelation(expr)
+	case *edgeCountExpression:
+		return c.compileEdgeCount(expr)
+	case *endpointExpression:
+		return c.compileEndpoint(expr)
 	default:
 		return nil, NewUnsupportedExpressionTypeError(valueTypeName(expr))
 	}
@@ -82,6 +97,20 @@ func (c queryCompiler) compileOr(expr *orExpression) (clause.Expression, error)
 	return clause.Or(clauses...), nil
 }
 
+// compileNot negates the compiled expression with IS NOT TRUE rather than NOT,
+// so that comparisons against NULL columns count as not matching instead of
+// being unknown.
+func (c queryCompiler) compileNot(expr *notExpression) (clause.Expression, error) {
+	inner, err := c.compile(expr.Expression)
+	if err != nil {
+		return nil, err
+	}
+	if inner == nil {
+		return nil, nil
+	}
+	return clause.Expr{SQL: "(?) IS NOT TRUE", Vars: []interface{}{inner}}, nil
+}
+
 func (c queryCompiler) compileComparison(expr *comparisionExpression) (clause.Expression, error) {
 	switch expr.Field.Source {
 	case SourceCore:
@@ -103,7 +132,10 @@ func (c queryCompiler) compileCoreComparison(expr *comparisionExpression) (claus
 		return nil, err
 	}
 	column := clause.Column{Table: prefix + "cores", Name: expr.Field.Name}
-	return compileScalarComparison(column, expr.Operator, expr.Value)
+	if expr.Operator == OperatorExists {
+		return compileScalarComparison(column, OperatorIsNotNull, nil)
+	}
+	return c.compileFieldComparison(column, expr)
 }
 
 func kvColumnName(fieldType ValueType) (string, error) {
@@ -112,6 +144,12 @@ func kvColumnName(fieldType ValueType) (string, error) {
 		return "value_text", nil
 	case ValueTypeInt:
 		return "value_int", nil
+	case ValueTypeInt64:
+		return "value_int64", nil
+	case ValueTypeFloat:
+		return "value_number", nil
+	case ValueTypeBool:
+		return "value_bool", nil
 	case ValueTypeTime:
 		return "value_time", nil
 	default:
@@ -131,17 +169,19 @@ func (c queryCompiler) compileKVComparison(expr *comparisionExpression) (clause.
 	}
 	id := prefix + "id"
 
-	column := clause.Column{Table: prefix + "kvs", Name: columnName}
-	scalarComperison, err := compileScalarComparison(column, expr.Operator, expr.Value)
-	if err != nil {
-		return nil, err
-	}
 	subquery := c.db.Session(&gorm.Session{NewDB: true}).
 		Table(prefix+"kvs").
 		Select("1").
 		Where(prefix+"kvs."+id+" = "+prefix+"cores.id").
-		Where(prefix+"kvs.key = ?", expr.Field.Name).
-		Where(scalarComperison)
+		Where(prefix+"kvs.key = ?", expr.Field.Name)
+	if expr.Operator != OperatorExists {
+		column := clause.Column{Table: prefix + "kvs", Name: columnName}
+		scalarComperison, err := c.compileFieldComparison(column, expr)
+		if err != nil {
+			return nil, err
+		}
+		subquery = subquery.Where(scalarComperison)
+	}
 	return clause.Expr{SQL: "EXISTS (?)", Vars: []interface{}{subquery}}, nil
 }
 
@@ -151,17 +191,19 @@ func (c queryCompiler) compileContentComparison(expr *comparisionExpression) (cl
 		return nil, err
 	}
 	id := prefix + "id"
-	column := clause.Column{Table: prefix + "contents", Name: "value"}
-	scalarComperison, err := compileScalarComparison(column, expr.Operator, expr.Value)
-	if err != nil {
-		return nil, err
-	}
 	subquery := c.db.Session(&gorm.Session{NewDB: true}).
 		Table(prefix+"contents").
 		Select("1").
 		Where(prefix+"contents."+id+" = "+prefix+"cores.id").
-		Where(prefix+"contents.key = ?", expr.Field.Name).
-		Where(scalarComperison)
+		Where(prefix+"contents.key = ?", expr.Field.Name)
+	if expr.Operator != OperatorExists {
+		column := clause.Column{Table: prefix + "contents", Name: "value"}
+		scalarComperison, err := c.compileFieldComparison(column, expr)
+		if err != nil {
+			return nil, err
+		}
+		subquery = subquery.Where(scalarComperison)
+	}
 	return clause.Expr{SQL: "EXISTS (?)", Vars: []interface{}{subquery}}, nil
 }
 
@@ -173,21 +215,91 @@ func (c queryCompiler) compileTagComparison(expr *comparisionExpression) (clause
 	}
 	id := prefix + "id"
 
-	scalarComperison, err := compileScalarComparison(column, expr.Operator, expr.Field.Name)
-	if err != nil {
-		return nil, err
-	}
 	subquery := c.db.Session(&gorm.Session{NewDB: true}).
 		Table(prefix + "tags").
 		Select("1").
 		Joins("JOIN tags ON tags.id = " + prefix + "tags.tag_id").
-		Where(prefix + "tags." + id + " = " + prefix + "cores.id").
-		Where(scalarComperison)
+		Where(prefix + "tags." + id + " = " + prefix + "cores.id")
+	if expr.Operator != OperatorExists {
+		scalarComperison, err := compileScalarComparison(column, expr.Operator, expr.Field.Name)
+		if err != nil {
+			return nil, err
+		}
+		subquery = subquery.Where(scalarComperison)
+	}
 	return clause.Expr{SQL: "EXISTS (?)", Vars: []interface{}{subquery}}, nil
 
 }
 
-func compileScalarComparison(column clause.Column, operator Operator, value any) (clause.Expression, error) {
+// compileFieldComparison compares column with the value of expr. Fields that
+// ignore case compare the lowered column with lowered values. SQLite's LIKE
+// ignores the case of ASCII letters, so case-sensitive patterns are matched
+// with GLOB there.
+func (c queryCompiler) compileFieldComparison(column clause.Column, expr *comparisionExpression) (clause.Expression, error) {
+	if !expr.Field.IgnoreCase {
+		if expr.Operator == OperatorLike && c.db.Dialector.Name() == "sqlite" {
+			pattern, ok := expr.Value.(string)
+			if !ok {
+				return nil, fmt.Errorf("value for 'like' operator must be a string")
+			}
+			return clause.Expr{SQL: "? GLOB ?", Vars: []interface{}{column, likeToGlob(pattern)}}, nil
+		}
+		return compileScalarComparison(column, expr.Operator, expr.Value)
+	}
+	value := expr.Value
+	if values, ok := value.([]any); ok {
+		lowered := make([]any, len(values))
+		for i, value := range values {
+			lowered[i] = lower(value)
+		}
+		value = lowered
+	} else {
+		value = lower(value)
+	}
+	return compileScalarComparison(lower(column), expr.Operator, value)
+}
+
+// likeToGlob translates a LIKE pattern with backslash escapes into a GLOB
+// pattern. GLOB wildcards in the pattern match literally.
+func likeToGlob(pattern string) string {
+	var b strings.Builder
+	escaped := false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			writeGlobLiteral(&b, c)
+		case c == '\\':
+			escaped = true
+		case c == '%':
+			b.WriteByte('*')
+		case c == '_':
+			b.WriteByte('?')
+		default:
+			writeGlobLiteral(&b, c)
+		}
+	}
+	if escaped {
+		b.WriteByte('\\')
+	}
+	return b.String()
+}
+
+func writeGlobLiteral(b *strings.Builder, c rune) {
+	if c == '*' || c == '?' || c == '[' {
+		b.WriteByte('[')
+		b.WriteRune(c)
+		b.WriteByte(']')
+		return
+	}
+	b.WriteRune(c)
+}
+
+func lower(value any) clause.Expr {
+	return clause.Expr{SQL: "LOWER(?)", Vars: []interface{}{value}}
+}
+
+func compileScalarComparison(column any, operator Operator, value any) (clause.Expression, error) {
 	switch operator {
 	case OperatorEqual:
 		return clause.Eq{Column: column, Value: value}, nil
@@ -213,6 +325,18 @@ func compileScalarComparison(column clause.Column, operator Operator, value any)
 			return nil, fmt.Errorf("value for 'not in' operator must be a slice")
 		}
 		return clause.Not(clause.IN{Column: column, Values: values}), nil
+	case OperatorBetween:
+		bounds, ok := value.([]any)
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("value for 'between' operator must be a lower and an upper bound")
+		}
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{column, bounds[0], bounds[1]}}, nil
+	case OperatorLike:
+		return clause.Expr{SQL: `? LIKE ? ESCAPE '\'`, Vars: []interface{}{column, value}}, nil
+	case OperatorIsNull:
+		return clause.Expr{SQL: "? IS NULL", Vars: []interface{}{column}}, nil
+	case OperatorIsNotNull:
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{column}}, nil
 	default:
 		return nil, fmt.Errorf("unsupported operator: %v", operator)
 	}
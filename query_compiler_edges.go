@@ -0,0 +1,124 @@
+package nod
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (c queryCompiler) compileEdgeRelation(expr *edgeRelationExpression) (clause.Expression, error) {
+	subquery, err := c.edgeRelationSubquery(expr, "1")
+	if err != nil {
+		return nil, err
+	}
+	return clause.Expr{SQL: "EXISTS (?)", Vars: []interface{}{subquery}}, nil
+}
+
+func (c queryCompiler) compileEdgeCount(expr *edgeCountExpression) (clause.Expression, error) {
+	operator, err := comparisonOperatorSQL(expr.Operator)
+	if err != nil {
+		return nil, err
+	}
+	subquery, err := c.edgeRelationSubquery(&expr.Edges, "COUNT(*)")
+	if err != nil {
+		return nil, err
+	}
+	return clause.Expr{SQL: "(?) " + operator + " ?", Vars: []interface{}{subquery, expr.Value}}, nil
+}
+
+// edgeRelationSubquery selects the edges of the outer node that match expr.
+// The node expression compiles against a nested node_cores scope, so it
+// applies to the node at the other end of each edge. Edges of symmetric kinds
+// match in either direction.
+func (c queryCompiler) edgeRelationSubquery(expr *edgeRelationExpression, selection string) (*gorm.DB, error) {
+	if c.scope != ScopeNode {
+		return nil, NewUnsupportedScopeError(c.scope)
+	}
+	if expr.Direction == DirectionBoth {
+		return nil, NewUnsupportedDirectionError(expr.Direction)
+	}
+
+	symmetric := c.schemas.symmetricKinds()
+	if expr.Kind != "" && !c.schemas.IsSymmetric(expr.Kind) {
+		symmetric = nil
+	}
+	follow, followVars, to, err := followSQL(`"edge_cores"`, `"node_cores"."id"`, expr.Direction, symmetric)
+	if err != nil {
+		return nil, err
+	}
+
+	subquery := c.db.Session(&gorm.Session{NewDB: true}).
+		Table("edge_cores").
+		Select(selection).
+		Where(follow, followVars...)
+	if expr.Kind != "" {
+		subquery = subquery.Where("edge_cores.kind = ?", expr.Kind)
+	}
+	if !isNilValue(expr.Node) {
+		nodeClause, err := c.compile(expr.Node)
+		if err != nil {
+			return nil, err
+		}
+		nodes := c.db.Session(&gorm.Session{NewDB: true}).
+			Table("node_cores").
+			Select("node_cores.id").
+			Where(nodeClause)
+		subquery = subquery.Where(to+" IN (?)", nodes)
+	}
+	return subquery, nil
+}
+
+// compileEndpoint compiles the node expression in node scope and selects the
+// matching node ids from a nested node_cores scope. A nil node expression
+// matches any node. Edges of symmetric kinds match when either endpoint
+// matches.
+func (c queryCompiler) compileEndpoint(expr *endpointExpression) (clause.Expression, error) {
+	if c.scope != ScopeEdge {
+		return nil, NewUnsupportedScopeError(c.scope)
+	}
+
+	nodes := c.db.Session(&gorm.Session{NewDB: true}).
+		Table("node_cores").
+		Select("node_cores.id")
+	if !isNilValue(expr.Node) {
+		nodeCompiler := queryCompiler{db: c.db, scope: ScopeNode, schemas: c.schemas, features: c.features}
+		nodeClause, err := nodeCompiler.compile(expr.Node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = nodes.Where(nodeClause)
+	}
+	symmetric := c.schemas.symmetricKinds()
+	if len(symmetric) == 0 {
+		return clause.Expr{SQL: "edge_cores." + expr.Column + " IN (?)", Vars: []interface{}{nodes}}, nil
+	}
+
+	other := "target_id"
+	if expr.Column == "target_id" {
+		other = "source_id"
+	}
+	return clause.Expr{
+		SQL:  "(edge_cores." + expr.Column + " IN (?) OR (edge_cores.kind IN ? AND edge_cores." + other + " IN (?)))",
+		Vars: []interface{}{nodes, symmetric, nodes},
+	}, nil
+}
+
+func comparisonOperatorSQL(operator Operator) (string, error) {
+	switch operator {
+	case OperatorEqual:
+		return "=", nil
+	case OperatorNotEqual:
+		return "<>", nil
+	case OperatorGreaterThan:
+		return ">", nil
+	case OperatorLessThan:
+		return "<", nil
+	case OperatorGreaterThanOrEqual:
+		return ">=", nil
+	case OperatorLessThanOrEqual:
+		return "<=", nil
+	default:
+		return "", fmt.Errorf("unsupported operator: %v", operator)
+	}
+}
@@ -0,0 +1,74 @@
+package nod
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (c queryCompiler) compileHierarchy(expr *hierarchyExpression) (clause.Expression, error) {
+	if c.scope != ScopeNode {
+		return nil, NewUnsupportedScopeError(c.scope)
+	}
+
+	if c.features.closure {
+		return c.compileClosureHierarchy(expr)
+	}
+
+	switch expr.Relation {
+	case hierarchyDescendantOf:
+		return clause.Expr{
+			SQL:  `"node_cores"."id" IN (` + descendantsCTE + ` SELECT "id" FROM "node_tree")`,
+			Vars: []interface{}{expr.NodeId, depthBound(expr.MaxDepth)},
+		}, nil
+	case hierarchyAncestorOf:
+		return clause.Expr{
+			SQL:  `"node_cores"."id" IN (` + ancestorsCTE + ` SELECT "id" FROM "node_tree")`,
+			Vars: []interface{}{expr.NodeId, hierarchyDepthLimit},
+		}, nil
+	default:
+		return nil, NewUnsupportedExpressionTypeError(valueTypeName(expr))
+	}
+}
+
+// compileClosureHierarchy answers hierarchy expressions from the closure table
+// with a single indexed lookup.
+func (c queryCompiler) compileClosureHierarchy(expr *hierarchyExpression) (clause.Expression, error) {
+	switch expr.Relation {
+	case hierarchyDescendantOf:
+		return clause.Expr{
+			SQL:  `"node_cores"."id" IN (SELECT "descendant_id" FROM "node_closures" WHERE "ancestor_id" = ? AND "depth" BETWEEN 1 AND ?)`,
+			Vars: []interface{}{expr.NodeId, depthBound(expr.MaxDepth)},
+		}, nil
+	case hierarchyAncestorOf:
+		return clause.Expr{
+			SQL:  `"node_cores"."id" IN (SELECT "ancestor_id" FROM "node_closures" WHERE "descendant_id" = ? AND "depth" > 0)`,
+			Vars: []interface{}{expr.NodeId},
+		}, nil
+	default:
+		return nil, NewUnsupportedExpressionTypeError(valueTypeName(expr))
+	}
+}
+
+// compileChildMatching selects parent ids from a nested node_cores scope, so
+// the child expression compiles against the children rather than the outer
+// query.
+func (c queryCompiler) compileChildMatching(expr *childMatchingExpression) (clause.Expression, error) {
+	if c.scope != ScopeNode {
+		return nil, NewUnsupportedScopeError(c.scope)
+	}
+
+	subquery := c.db.Session(&gorm.Session{NewDB: true}).
+		Table("node_cores").
+		Select("node_cores.parent_id").
+		Where("node_cores.parent_id IS NOT NULL")
+	if !isNilValue(expr.Expression) {
+		childClause, err := c.compile(expr.Expression)
+		if err != nil {
+			return nil, err
+		}
+		if childClause != nil {
+			subquery = subquery.Where(childClause)
+		}
+	}
+	return clause.Expr{SQL: "node_cores.id IN (?)", Vars: []interface{}{subquery}}, nil
+}
@@ -0,0 +1,73 @@
+package nod
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// encodeCursor packs the sort key values of a row into an opaque token.
+func encodeCursor(values []any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor unpacks a token created by encodeCursor into values of the
+// types the sort keys compare.
+func decodeCursor(cursor string, keys []ordering) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, NewInvalidCursorError("not base64 encoded")
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, NewInvalidCursorError("not a list of values")
+	}
+	if len(raw) != len(keys) {
+		return nil, NewInvalidCursorError("expected " + strconv.Itoa(len(keys)) + " values, got " + strconv.Itoa(len(raw)))
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		if string(raw[i]) == "null" {
+			continue
+		}
+		value, err := decodeCursorValue(raw[i], key.ref)
+		if err != nil {
+			return nil, NewInvalidCursorError("value " + strconv.Itoa(i) + " does not match its sort key")
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func decodeCursorValue(raw json.RawMessage, ref FieldRef) (any, error) {
+	valueType := ref.Type
+	if ref.IgnoreCase {
+		valueType = ValueTypeString
+	}
+	switch valueType {
+	case ValueTypeInt, ValueTypeInt64:
+		return unmarshalCursorValue[int64](raw)
+	case ValueTypeFloat:
+		return unmarshalCursorValue[float64](raw)
+	case ValueTypeBool:
+		return unmarshalCursorValue[bool](raw)
+	case ValueTypeTime:
+		return unmarshalCursorValue[time.Time](raw)
+	default:
+		return unmarshalCursorValue[string](raw)
+	}
+}
+
+func unmarshalCursorValue[T any](raw json.RawMessage) (any, error) {
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,364 @@
+package nod
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SortDirection selects the order of a sort key.
+type SortDirection uint8
+
+const (
+	// Ascending sorts from the lowest to the highest value.
+	Ascending SortDirection = iota
+	// Descending sorts from the highest to the lowest value.
+	Descending
+)
+
+// OrderField is a field node and edge queries can be ordered by: core fields,
+// timestamps, KV fields and content.
+type OrderField interface {
+	orderRef() FieldRef
+}
+
+func (f StringField) orderRef() FieldRef    { return f.ref }
+func (f TimeField) orderRef() FieldRef      { return f.ref }
+func (f NumberField[T]) orderRef() FieldRef { return f.ref }
+func (f BoolField) orderRef() FieldRef      { return f.ref }
+
+// Page is one page of query results. Passing NextCursor to After continues
+// the query behind the last item; it is empty on the last page.
+type Page[T any] struct {
+	Items      []*T
+	NextCursor string
+}
+
+type ordering struct {
+	ref       FieldRef
+	direction SortDirection
+}
+
+// pagination holds the sort keys, window and cursor of a node or edge query.
+type pagination struct {
+	order  []ordering
+	limit  int
+	offset int
+	after  string
+}
+
+func (p *pagination) orderBy(field OrderField, direction SortDirection) {
+	p.order = append(p.order, ordering{ref: field.orderRef(), direction: direction})
+}
+
+// ordered reports whether results are sorted, which every paginated query is.
+func (p pagination) ordered() bool {
+	return len(p.order) > 0 || p.limit > 0 || p.offset > 0 || p.after != ""
+}
+
+// keys returns the sort keys followed by the id, which breaks ties and makes
+// every cursor point at a single row.
+func (p pagination) keys() []ordering {
+	return append(slices.Clone(p.order), ordering{ref: FieldRef{Source: SourceCore, Type: ValueTypeString, Name: "id"}})
+}
+
+// apply sorts db by the sort keys, with NULL values last in either direction,
+// skips the rows up to the cursor and applies the window. A limit above zero
+// replaces the limit of the query. An offset cannot be combined with a cursor.
+func (p pagination) apply(db *gorm.DB, scope Scope, limit int) (*gorm.DB, error) {
+	if limit <= 0 {
+		limit = p.limit
+	}
+	if !p.ordered() {
+		if limit > 0 {
+			db = db.Limit(limit)
+		}
+		return db, nil
+	}
+	if p.after != "" && p.offset > 0 {
+		return nil, NewOffsetWithCursorError()
+	}
+
+	keys, exprs, err := p.sortExpressions(scope)
+	if err != nil {
+		return nil, err
+	}
+	sql := make([]string, 0, 2*len(keys))
+	var vars []interface{}
+	for i, key := range keys {
+		direction := "ASC"
+		if key.direction == Descending {
+			direction = "DESC"
+		}
+		sql = append(sql, "CASE WHEN ? IS NULL THEN 1 ELSE 0 END", "? "+direction)
+		vars = append(vars, exprs[i], exprs[i])
+	}
+	db = db.Clauses(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(sql, ", "), Vars: vars}})
+
+	if p.after != "" {
+		values, err := decodeCursor(p.after, keys)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(afterCursor(keys, exprs, values))
+	}
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if p.offset > 0 {
+		db = db.Offset(p.offset)
+	}
+	return db, nil
+}
+
+// sortExpressions returns the sort keys together with the SQL values they
+// sort by.
+func (p pagination) sortExpressions(scope Scope) ([]ordering, []interface{}, error) {
+	prefix, err := scopePrefix(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := p.keys()
+	exprs := make([]interface{}, len(keys))
+	for i, key := range keys {
+		exprs[i], err = sortExpression(prefix, key.ref)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, exprs, nil
+}
+
+// afterCursor matches the rows sorted behind the row whose sort key values
+// are values. NULL values sort last, so nothing sorts behind a NULL in the
+// same key.
+func afterCursor(keys []ordering, exprs []interface{}, values []any) clause.Expression {
+	var behind, equal []clause.Expression
+	for i, key := range keys {
+		if values[i] == nil {
+			equal = append(equal, clause.Expr{SQL: "? IS NULL", Vars: []interface{}{exprs[i]}})
+			continue
+		}
+		operator := ">"
+		if key.direction == Descending {
+			operator = "<"
+		}
+		after := clause.Expr{SQL: "(? " + operator + " ? OR ? IS NULL)", Vars: []interface{}{exprs[i], values[i], exprs[i]}}
+		behind = append(behind, clause.And(append(slices.Clone(equal), after)...))
+		equal = append(equal, clause.Expr{SQL: "? = ?", Vars: []interface{}{exprs[i], values[i]}})
+	}
+	return clause.Or(behind...)
+}
+
+// sortExpression returns the SQL value a field is sorted by. KV and content
+// fields are read with a correlated subquery, which is NULL for rows without
+// the key.
+func sortExpression(prefix string, ref FieldRef) (interface{}, error) {
+	var expr interface{}
+	switch ref.Source {
+	case SourceCore:
+		expr = clause.Column{Table: prefix + "cores", Name: ref.Name}
+	case SourceKV, SourceContent:
+		table, column, err := sortTable(prefix, ref)
+		if err != nil {
+			return nil, err
+		}
+		expr = clause.Expr{
+			SQL: "(SELECT ? FROM ? WHERE ? = ? AND ? = ?)",
+			Vars: []interface{}{
+				clause.Column{Table: table, Name: column},
+				clause.Table{Name: table},
+				clause.Column{Table: table, Name: prefix + "id"},
+				clause.Column{Table: prefix + "cores", Name: "id"},
+				clause.Column{Table: table, Name: "key"},
+				ref.Name,
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported order field source: %v", ref.Source)
+	}
+	if ref.IgnoreCase {
+		return lower(expr), nil
+	}
+	return expr, nil
+}
+
+// sortTable returns the table and column holding the values of a KV or
+// content field.
+func sortTable(prefix string, ref FieldRef) (string, string, error) {
+	if ref.Source == SourceContent {
+		return prefix + "contents", "value", nil
+	}
+	column, err := kvColumnName(ref.Type)
+	if err != nil {
+		return "", "", err
+	}
+	return prefix + "kvs", column, nil
+}
+
+// pageIds runs the page query on db, which reads the cores table of scope,
+// and returns the ids of at most limit rows in order. The query selects the
+// sort key values of every row, and the cursor of the next page is encoded
+// from the values of the last returned row. The cursor is empty when no row
+// follows.
+func (p pagination) pageIds(db *gorm.DB, scope Scope) ([]string, string, error) {
+	prefix, err := scopePrefix(scope)
+	if err != nil {
+		return nil, "", err
+	}
+	keys, exprs, err := p.sortExpressions(scope)
+	if err != nil {
+		return nil, "", err
+	}
+	db, err = p.apply(db.Table(prefix+"cores"), scope, p.limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	columns := make([]string, len(exprs))
+	for i := range exprs {
+		columns[i] = "? AS " + sortColumn(i)
+	}
+
+	rows, err := db.Select(strings.Join(columns, ", "), exprs...).Rows()
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var ids []string
+	var last []any
+	more := false
+	for rows.Next() {
+		if len(ids) == p.limit {
+			more = true
+			break
+		}
+		raw := make([]any, len(keys))
+		dest := make([]any, len(keys))
+		for i := range raw {
+			dest[i] = &raw[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, "", err
+		}
+		values, err := sortKeyValues(keys, raw)
+		if err != nil {
+			return nil, "", err
+		}
+		ids = append(ids, values[len(values)-1].(string))
+		last = values
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	if !more {
+		return ids, "", nil
+	}
+
+	cursor, err := encodeCursor(last)
+	if err != nil {
+		return nil, "", err
+	}
+	return ids, cursor, nil
+}
+
+func sortColumn(i int) string {
+	return "nod_sort_" + strconv.Itoa(i)
+}
+
+// sortKeyValues converts the sort key values read from the database into the
+// types decodeCursor restores from a cursor. SQLite returns values read by a
+// subquery without their declared type, so times may arrive as text.
+func sortKeyValues(keys []ordering, raw []any) ([]any, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		if raw[i] == nil {
+			continue
+		}
+		valueType := key.ref.Type
+		if key.ref.IgnoreCase {
+			valueType = ValueTypeString
+		}
+
+		var ok bool
+		switch valueType {
+		case ValueTypeString:
+			values[i], ok = textValue(raw[i])
+		case ValueTypeInt, ValueTypeInt64:
+			values[i], ok = raw[i].(int64)
+		case ValueTypeFloat:
+			switch value := raw[i].(type) {
+			case float64:
+				values[i], ok = value, true
+			case int64:
+				values[i], ok = float64(value), true
+			}
+		case ValueTypeBool:
+			switch value := raw[i].(type) {
+			case bool:
+				values[i], ok = value, true
+			case int64:
+				values[i], ok = value != 0, true
+			}
+		case ValueTypeTime:
+			if value, isTime := raw[i].(time.Time); isTime {
+				values[i], ok = value, true
+			} else if text, isText := textValue(raw[i]); isText {
+				values[i], ok = parseTimeText(text)
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("unsupported value %T for order field %s", raw[i], key.ref.Name)
+		}
+	}
+	return values, nil
+}
+
+func textValue(raw any) (string, bool) {
+	switch value := raw.(type) {
+	case string:
+		return value, true
+	case []byte:
+		return string(value), true
+	default:
+		return "", false
+	}
+}
+
+// timeTextLayouts are the layouts times are stored with as text.
+var timeTextLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseTimeText(text string) (time.Time, bool) {
+	for _, layout := range timeTextLayouts {
+		if value, err := time.Parse(layout, text); err == nil {
+			return value, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// orderByIds returns the cores in the order of ids, skipping ids without a core.
+func orderByIds[C any](cores []*C, ids []string, id func(*C) string) []*C {
+	byId := make(map[string]*C, len(cores))
+	for _, core := range cores {
+		byId[id(core)] = core
+	}
+	ordered := make([]*C, 0, len(ids))
+	for _, id := range ids {
+		if core, ok := byId[id]; ok {
+			ordered = append(ordered, core)
+		}
+	}
+	return ordered
+}
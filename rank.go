@@ -0,0 +1,76 @@
+package nod
+
+import "strings"
+
+// rankDigits are the digits of sibling ranks in ascending byte order, so ranks
+// compare correctly as plain strings.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// rankBetween returns a rank that sorts strictly between a and b. An empty a
+// means no lower bound and an empty b means no upper bound. Generated ranks
+// never end with the lowest digit, which keeps room for a rank between any two
+// of them.
+func rankBetween(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankBetween(a[min(n, len(a)):], b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(rankDigits[digitA]) + rankBetween(rest, "")
+}
+
+// ranksBetween returns n ascending ranks between a and b. Splitting the range
+// in halves keeps the ranks short even for many siblings.
+func ranksBetween(a, b string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	middle := rankBetween(a, b)
+	ranks := ranksBetween(a, middle, n/2)
+	ranks = append(ranks, middle)
+	return append(ranks, ranksBetween(middle, b, n-n/2-1)...)
+}
+
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+func isValidRank(rank string) bool {
+	if rank == "" || rank[len(rank)-1] == rankDigits[0] {
+		return false
+	}
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
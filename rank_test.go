@@ -0,0 +1,76 @@
+package nod
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"i", ""},
+		{"z", ""},
+		{"", "i"},
+		{"", "1"},
+		{"", "01"},
+		{"i", "j"},
+		{"i", "i1"},
+		{"az", "b"},
+		{"zz", ""},
+	}
+
+	for _, tt := range tests {
+		rank := rankBetween(tt.a, tt.b)
+		require.True(t, isValidRank(rank), "rank %q between %q and %q", rank, tt.a, tt.b)
+		require.Greater(t, rank, tt.a)
+		if tt.b != "" {
+			require.Less(t, rank, tt.b)
+		}
+	}
+}
+
+func TestRankBetweenKeepsOrderForRandomInserts(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	ranks := []string{}
+
+	for range 2000 {
+		index := random.Intn(len(ranks) + 1)
+		lower, upper := "", ""
+		if index > 0 {
+			lower = ranks[index-1]
+		}
+		if index < len(ranks) {
+			upper = ranks[index]
+		}
+
+		rank := rankBetween(lower, upper)
+		require.True(t, isValidRank(rank))
+		ranks = append(ranks[:index], append([]string{rank}, ranks[index:]...)...)
+	}
+
+	require.True(t, sort.StringsAreSorted(ranks))
+}
+
+func TestRanksBetween(t *testing.T) {
+	ranks := ranksBetween("", "", 1000)
+
+	require.Len(t, ranks, 1000)
+	require.True(t, sort.StringsAreSorted(ranks))
+	for i, rank := range ranks {
+		require.True(t, isValidRank(rank))
+		require.LessOrEqual(t, len(rank), 4)
+		if i > 0 {
+			require.NotEqual(t, ranks[i-1], rank)
+		}
+	}
+
+	between := ranksBetween("a", "b", 10)
+	require.Len(t, between, 10)
+	require.Greater(t, between[0], "a")
+	require.Less(t, between[9], "b")
+}
@@ -0,0 +1,191 @@
+package nod
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// RelationScope links typed source models of type A to typed target models of
+// type B through edges of one kind. Both sides are encoded and decoded with the
+// codec of the model or the adapter registered in the repository.
+type RelationScope[A any, B any] struct {
+	repository *Repository
+	kind       string
+}
+
+// Relation returns a RelationScope for edges of the given kind from models of
+// type A to models of type B.
+func Relation[A any, B any](repository *Repository, kind string) *RelationScope[A, B] {
+	return &RelationScope[A, B]{
+		repository: repository,
+		kind:       kind,
+	}
+}
+
+// Link creates an edge from source to target in the namespace of source. Both
+// models must already be saved. Nothing is created when the edge exists
+// already. The check and the save run in one transaction, with the two nodes
+// locked, so concurrent links of the same models create a single edge.
+func (scope *RelationScope[A, B]) Link(source *A, target *B) error {
+	sourceId, targetId, err := scope.endpoints(source, target)
+	if err != nil {
+		return err
+	}
+
+	return scope.repository.Transaction(func(txRepository *Repository) error {
+		tx := Relation[A, B](txRepository, scope.kind)
+		if err := lockEdgeEndpoints(txRepository.db, &EdgeCore{SourceId: sourceId, TargetId: targetId}); err != nil {
+			return err
+		}
+
+		var existing EdgeCore
+		err := tx.edges().Select("id").Where(tx.between(sourceId, targetId)).Take(&existing).Error
+		switch {
+		case err == nil:
+			return nil
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		var sourceNode NodeCore
+		if err := txRepository.db.Select("namespace_id").First(&sourceNode, "id = ?", sourceId).Error; err != nil {
+			return err
+		}
+		_, err = txRepository.Edges().SaveEdge(&Edge{Core: EdgeCore{
+			NamespaceId: sourceNode.NamespaceId,
+			SourceId:    sourceId,
+			TargetId:    targetId,
+			Kind:        scope.kind,
+		}})
+		// The unique index of a unique kind rejects an edge linked in the
+		// meantime, which is the edge Link would have created.
+		var duplicateErr *DuplicateEdgeError
+		if errors.As(err, &duplicateErr) {
+			return nil
+		}
+		return err
+	})
+}
+
+// Unlink deletes every edge from source to target, and from target to source
+// when the kind is symmetric. The edges are deleted one by one with
+// EdgeScope.DeleteEdge, in one transaction.
+func (scope *RelationScope[A, B]) Unlink(source *A, target *B) error {
+	sourceId, targetId, err := scope.endpoints(source, target)
+	if err != nil {
+		return err
+	}
+
+	return scope.repository.Transaction(func(txRepository *Repository) error {
+		tx := Relation[A, B](txRepository, scope.kind)
+		var ids []string
+		if err := tx.edges().Where(tx.between(sourceId, targetId)).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := txRepository.Edges().DeleteEdge(&Edge{Core: EdgeCore{Id: id}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// TargetsOf returns the models linked from source, in the order they were
+// linked.
+func (scope *RelationScope[A, B]) TargetsOf(source *A) ([]*B, error) {
+	id, err := relationNodeId(scope.repository, source)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := scope.linked(id, false)
+	if err != nil {
+		return nil, err
+	}
+	return Nodes[B](scope.repository).GetNodes(ids)
+}
+
+// SourcesOf returns the models linking to target, in the order they were
+// linked.
+func (scope *RelationScope[A, B]) SourcesOf(target *B) ([]*A, error) {
+	id, err := relationNodeId(scope.repository, target)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := scope.linked(id, true)
+	if err != nil {
+		return nil, err
+	}
+	return Nodes[A](scope.repository).GetNodes(ids)
+}
+
+func (scope *RelationScope[A, B]) edges() *gorm.DB {
+	return scope.repository.db.Model(&EdgeCore{}).Where("kind = ?", scope.kind)
+}
+
+// between selects the edges from sourceId to targetId, and those in the other
+// direction when the kind is symmetric.
+func (scope *RelationScope[A, B]) between(sourceId, targetId string) *gorm.DB {
+	db := scope.repository.db.Session(&gorm.Session{NewDB: true}).Where("source_id = ? AND target_id = ?", sourceId, targetId)
+	if scope.repository.edgeSchemas.IsSymmetric(scope.kind) {
+		db = db.Or("source_id = ? AND target_id = ?", targetId, sourceId)
+	}
+	return db
+}
+
+func (scope *RelationScope[A, B]) endpoints(source *A, target *B) (string, string, error) {
+	sourceId, err := relationNodeId(scope.repository, source)
+	if err != nil {
+		return "", "", err
+	}
+	targetId, err := relationNodeId(scope.repository, target)
+	if err != nil {
+		return "", "", err
+	}
+	return sourceId, targetId, nil
+}
+
+// linked returns the distinct ids of the nodes at the other end of the edges
+// leaving the node with the given id, or entering it when incoming is true,
+// ordered by the first edge that links them. Edges of symmetric kinds are
+// followed in both directions.
+func (scope *RelationScope[A, B]) linked(id string, incoming bool) ([]string, error) {
+	db := scope.edges()
+	switch {
+	case scope.repository.edgeSchemas.IsSymmetric(scope.kind):
+		db = db.Where("(source_id = ? OR target_id = ?)", id, id)
+	case incoming:
+		db = db.Where("target_id = ?", id)
+	default:
+		db = db.Where("source_id = ?", id)
+	}
+
+	var cores []*EdgeCore
+	if err := db.Order("created_at").Order("id").Find(&cores).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(cores))
+	ids := make([]string, 0, len(cores))
+	for _, core := range cores {
+		linkedId := otherEndpoint(core, id)
+		if !seen[linkedId] {
+			seen[linkedId] = true
+			ids = append(ids, linkedId)
+		}
+	}
+	return ids, nil
+}
+
+// relationNodeId returns the id of the node a model is stored as.
+func relationNodeId[T any](repository *Repository, model *T) (string, error) {
+	node, err := nodeFromModel(repository.adapters, model)
+	if err != nil {
+		return "", err
+	}
+	if node.Core.Id == "" {
+		return "", NewModelIdIsEmptyError(modelTypeName[T]())
+	}
+	return node.Core.Id, nil
+}
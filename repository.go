@@ -7,24 +7,30 @@ import (
 )
 
 type Repository struct {
-	db       *gorm.DB
-	log      *slog.Logger
-	adapters *AdapterRegistry
+	db          *gorm.DB
+	log         *slog.Logger
+	adapters    *AdapterRegistry
+	edgeSchemas *EdgeSchemaRegistry
+	features    *featureCache
 }
 
 func NewRepository(db *gorm.DB, log *slog.Logger) *Repository {
 	return &Repository{
-		db:       db,
-		log:      log,
-		adapters: NewAdapterRegistry(),
+		db:          db,
+		log:         log,
+		adapters:    NewAdapterRegistry(),
+		edgeSchemas: NewEdgeSchemaRegistry(),
+		features:    &featureCache{},
 	}
 }
 
 func NewRepositoryWithAdapters(db *gorm.DB, log *slog.Logger, adapters *AdapterRegistry) *Repository {
 	return &Repository{
-		db:       db,
-		log:      log,
-		adapters: adapters,
+		db:          db,
+		log:         log,
+		adapters:    adapters,
+		edgeSchemas: NewEdgeSchemaRegistry(),
+		features:    &featureCache{},
 	}
 }
 
@@ -37,15 +43,41 @@ func (r *Repository) Log() *slog.Logger { return r.log }
 // Adapters returns the repository's adapter registry.
 func (r *Repository) Adapters() *AdapterRegistry { return r.adapters }
 
+// EdgeSchemas returns the repository's edge schema registry.
+func (r *Repository) EdgeSchemas() *EdgeSchemaRegistry { return r.edgeSchemas }
+
+// Migrate migrates the repository's database like Migrate. A repository reads
+// the enabled options once, so options enabled on a database that is already
+// in use have to be migrated through its repository.
+func (r *Repository) Migrate(options ...MigrateOption) error {
+	if err := Migrate(r.db, options...); err != nil {
+		return err
+	}
+	features, err := readSchemaFeatures(r.db)
+	if err != nil {
+		return err
+	}
+	r.features.store(features)
+	return nil
+}
+
+// enabledFeatures returns the optional schema features of the repository's
+// database.
+func (r *Repository) enabledFeatures() (schemaFeatures, error) {
+	return r.features.load(r.db)
+}
+
 // Transaction executes fn in a database transaction. The repository passed to
-// fn uses the transactional database handle and preserves the logger and
-// adapter registry of the parent repository.
+// fn uses the transactional database handle and preserves the logger, adapter
+// registry and edge schema registry of the parent repository.
 func (r *Repository) Transaction(fn func(txRepository *Repository) error) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		return fn(&Repository{
-			db:       tx,
-			log:      r.log,
-			adapters: r.adapters,
+			db:          tx,
+			log:         r.log,
+			adapters:    r.adapters,
+			edgeSchemas: r.edgeSchemas,
+			features:    r.features,
 		})
 	})
 }
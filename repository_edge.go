@@ -1,6 +1,8 @@
 package nod
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -29,7 +31,12 @@ func (scope *EdgeScope[T]) Query() *TypedEdgeQuery[T] {
 	return NewTypedEdgeQuery[T](scope.repository)
 }
 
-// SaveEdge saves the given edge to the repository.
+// SaveEdge saves the given edge to the repository. Edges of a kind with a
+// registered EdgeSchema fail with EdgeEndpointKindError, DuplicateEdgeError or
+// EdgeCardinalityError when they break the schema. The endpoint nodes are
+// locked while the schema is checked, and a repeated pair of a unique kind
+// that a concurrent save slips past the check is rejected by a unique index,
+// also with DuplicateEdgeError.
 func (scope *EdgeScope[T]) SaveEdge(model *T) (string, error) {
 	if model == nil {
 		return "", NewEdgeIsNilError()
@@ -42,7 +49,11 @@ func (scope *EdgeScope[T]) SaveEdge(model *T) (string, error) {
 
 	id := ensureEdgeID(edge)
 	err = scope.repository.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(&edge.Core).Error; err != nil {
+		if err := checkEdgeSchema(tx, scope.repository.edgeSchemas, &edge.Core); err != nil {
+			return err
+		}
+		edge.Core.UniquePair = uniqueEdgePair(scope.repository.edgeSchemas, &edge.Core)
+		if err := saveEdgeCore(tx, &edge.Core); err != nil {
 			return err
 		}
 
@@ -96,7 +107,9 @@ func (scope *EdgeScope[T]) SaveEdge(model *T) (string, error) {
 	return id, err
 }
 
-// DeleteEdge deletes the given edge from the repository.
+// DeleteEdge deletes the given edge from the repository. For symmetric kinds
+// every edge of the kind between the same two nodes is deleted, in either
+// direction.
 func (scope *EdgeScope[T]) DeleteEdge(model *T) error {
 	if model == nil {
 		return NewEdgeIsNilError()
@@ -107,10 +120,37 @@ func (scope *EdgeScope[T]) DeleteEdge(model *T) error {
 		return err
 	}
 	return scope.repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteSymmetricEdges(tx, scope.repository.edgeSchemas, edge.Core.Id); err != nil {
+			return err
+		}
 		return tx.Delete(&edge.Core).Error
 	})
 }
 
+// deleteSymmetricEdges deletes the edges that link the same nodes as the edge
+// with the given id when its kind is symmetric.
+func deleteSymmetricEdges(tx *gorm.DB, schemas *EdgeSchemaRegistry, id string) error {
+	if len(schemas.symmetricKinds()) == 0 {
+		return nil
+	}
+
+	var stored EdgeCore
+	err := tx.Select("kind", "source_id", "target_id").Take(&stored, "id = ?", id).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil
+	case err != nil:
+		return err
+	case !schemas.IsSymmetric(stored.Kind):
+		return nil
+	}
+
+	return tx.Where("kind = ? AND id <> ?", stored.Kind, id).
+		Where("(source_id = ? AND target_id = ?) OR (source_id = ? AND target_id = ?)",
+			stored.SourceId, stored.TargetId, stored.TargetId, stored.SourceId).
+		Delete(&EdgeCore{}).Error
+}
+
 func (scope *EdgeScope[T]) GetEdge(id string) (*T, error) {
 	edge := &Edge{}
 	if err := scope.repository.db.First(&edge.Core, "id = ?", id).Error; err != nil {
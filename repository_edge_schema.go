@@ -0,0 +1,150 @@
+package nod
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// checkEdgeSchema enforces the schema registered for the kind of the edge. The
+// edge itself is left out of the uniqueness and cardinality counts, so saving
+// an existing edge again does not fail. Symmetric kinds are unique per pair of
+// nodes in either order.
+//
+// Before counting, the endpoint nodes are locked for the rest of the
+// transaction, so concurrent saves of edges at the same nodes cannot both pass
+// a cardinality limit. SQLite has no row locks and relies on allowing a single
+// writer at a time instead. Uniqueness is also backed by the unique index on
+// EdgeCore.UniquePair.
+func checkEdgeSchema(tx *gorm.DB, schemas *EdgeSchemaRegistry, edge *EdgeCore) error {
+	schema, ok := schemas.Lookup(edge.Kind)
+	if !ok {
+		return nil
+	}
+	if schema.Unique || schema.Symmetric || schema.MaxOutgoing > 0 || schema.MaxIncoming > 0 {
+		if err := lockEdgeEndpoints(tx, edge); err != nil {
+			return err
+		}
+	}
+
+	if err := checkEdgeEndpointKind(tx, schema, "source", edge.SourceId, schema.SourceKinds); err != nil {
+		return err
+	}
+	if err := checkEdgeEndpointKind(tx, schema, "target", edge.TargetId, schema.TargetKinds); err != nil {
+		return err
+	}
+
+	others := func() *gorm.DB {
+		return tx.Model(&EdgeCore{}).Where("kind = ? AND id <> ?", edge.Kind, edge.Id)
+	}
+
+	if schema.Unique || schema.Symmetric {
+		pair := tx.Session(&gorm.Session{NewDB: true}).Where("source_id = ? AND target_id = ?", edge.SourceId, edge.TargetId)
+		if schema.Symmetric {
+			pair = pair.Or("source_id = ? AND target_id = ?", edge.TargetId, edge.SourceId)
+		}
+		var existing EdgeCore
+		err := others().Select("id").Where(pair).Take(&existing).Error
+		switch {
+		case err == nil:
+			return NewDuplicateEdgeError(edge.Kind, edge.SourceId, edge.TargetId, existing.Id)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+	}
+
+	// Edges of symmetric kinds count at both of their endpoints.
+	touching := func(column, nodeId string) *gorm.DB {
+		if schema.Symmetric {
+			return others().Where("(source_id = ? OR target_id = ?)", nodeId, nodeId)
+		}
+		return others().Where(column+" = ?", nodeId)
+	}
+
+	if schema.MaxOutgoing > 0 {
+		var count int64
+		if err := touching("source_id", edge.SourceId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(schema.MaxOutgoing) {
+			return NewEdgeCardinalityError(edge.Kind, edge.SourceId, DirectionOutgoing, schema.MaxOutgoing)
+		}
+	}
+	if schema.MaxIncoming > 0 {
+		var count int64
+		if err := touching("target_id", edge.TargetId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(schema.MaxIncoming) {
+			return NewEdgeCardinalityError(edge.Kind, edge.TargetId, DirectionIncoming, schema.MaxIncoming)
+		}
+	}
+	return nil
+}
+
+// saveEdgeCore saves the core of an edge in a savepoint. When the save fails
+// and another edge holds the same UniquePair, the unique index rejected the
+// pair and the failure is reported as DuplicateEdgeError.
+func saveEdgeCore(tx *gorm.DB, core *EdgeCore) error {
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		return tx.Save(core).Error
+	})
+	if err == nil || core.UniquePair == nil {
+		return err
+	}
+
+	var existing EdgeCore
+	if tx.Select("id").Where("unique_pair = ? AND id <> ?", *core.UniquePair, core.Id).Take(&existing).Error != nil {
+		return err
+	}
+	return NewDuplicateEdgeError(core.Kind, core.SourceId, core.TargetId, existing.Id)
+}
+
+// lockEdgeEndpoints locks the rows of the source and target node of the edge
+// until the transaction ends. The rows are locked in id order, so concurrent
+// saves of edges between the same nodes in either direction cannot deadlock.
+func lockEdgeEndpoints(tx *gorm.DB, edge *EdgeCore) error {
+	var ids []string
+	return tx.Model(&NodeCore{}).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("id IN ?", []string{edge.SourceId, edge.TargetId}).
+		Order("id").
+		Pluck("id", &ids).Error
+}
+
+// uniqueEdgePair returns the value of EdgeCore.UniquePair for the edge: a hash
+// of its kind and endpoints when its schema allows a single edge between two
+// nodes, and nil otherwise. Endpoints of symmetric kinds are hashed in sorted
+// order, so both directions share the value.
+func uniqueEdgePair(schemas *EdgeSchemaRegistry, edge *EdgeCore) *string {
+	schema, ok := schemas.Lookup(edge.Kind)
+	if !ok || !(schema.Unique || schema.Symmetric) {
+		return nil
+	}
+	first, second := edge.SourceId, edge.TargetId
+	if schema.Symmetric && second < first {
+		first, second = second, first
+	}
+	sum := sha256.Sum256([]byte(edge.Kind + "\x00" + first + "\x00" + second))
+	pair := hex.EncodeToString(sum[:])
+	return &pair
+}
+
+func checkEdgeEndpointKind(tx *gorm.DB, schema EdgeSchema, endpoint, nodeId string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	var node NodeCore
+	if err := tx.Select("kind").First(&node, "id = ?", nodeId).Error; err != nil {
+		return err
+	}
+	if !slices.Contains(allowed, node.Kind) {
+		return NewEdgeEndpointKindError(schema.Kind, endpoint, nodeId, node.Kind, allowed)
+	}
+	return nil
+}
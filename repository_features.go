@@ -0,0 +1,62 @@
+package nod
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// schemaFeatures are the optional parts of the schema enabled by Migrate.
+type schemaFeatures struct {
+	closure            bool
+	uniqueSiblingNames bool
+}
+
+// featureCache keeps the schema features of a repository's database. They
+// are read on first use and only read again when the repository migrates the
+// database.
+type featureCache struct {
+	mu       sync.Mutex
+	loaded   bool
+	features schemaFeatures
+}
+
+func (c *featureCache) load(db *gorm.DB) (schemaFeatures, error) {
+	c.mu.Lock()
+	if c.loaded {
+		defer c.mu.Unlock()
+		return c.features, nil
+	}
+	c.mu.Unlock()
+
+	features, err := readSchemaFeatures(db)
+	if err != nil {
+		return schemaFeatures{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded {
+		c.features, c.loaded = features, true
+	}
+	return c.features, nil
+}
+
+func (c *featureCache) store(features schemaFeatures) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.features, c.loaded = features, true
+}
+
+func readSchemaFeatures(db *gorm.DB) (schemaFeatures, error) {
+	db = db.Session(&gorm.Session{NewDB: true})
+	closure, err := readFlagProperty(db, nodeClosurePropertyKey)
+	if err != nil {
+		return schemaFeatures{}, err
+	}
+	uniqueSiblingNames, err := readFlagProperty(db, uniqueSiblingNamesPropertyKey)
+	if err != nil {
+		return schemaFeatures{}, err
+	}
+	return schemaFeatures{closure: closure, uniqueSiblingNames: uniqueSiblingNames}, nil
+}
@@ -0,0 +1,195 @@
+package nod
+
+import "gorm.io/gorm"
+
+// edgeFilter restricts the edges a graph walk may follow to the given kinds
+// and to edges matching an edge expression.
+type edgeFilter struct {
+	kinds []string
+	where Expression
+}
+
+func (f *edgeFilter) addKinds(kinds []string) {
+	f.kinds = append(f.kinds, kinds...)
+}
+
+func (f *edgeFilter) addWhere(expr Expression) {
+	if expr == nil {
+		return
+	}
+	if f.where == nil {
+		f.where = expr
+	} else {
+		f.where = And(f.where, expr)
+	}
+}
+
+// condition returns SQL restricting edge_cores rows to the filter, together
+// with its vars.
+func (f edgeFilter) condition(repository *Repository) (string, []interface{}, error) {
+	if len(f.kinds) == 0 && f.where == nil {
+		return "1 = 1", nil, nil
+	}
+
+	subquery := repository.db.Session(&gorm.Session{NewDB: true}).Model(&EdgeCore{}).Select("edge_cores.id")
+	if len(f.kinds) > 0 {
+		subquery = subquery.Where("edge_cores.kind IN ?", f.kinds)
+	}
+	if f.where != nil {
+		var err error
+		subquery, err = applyExpression(subquery, f.where, ScopeEdge, repository)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return `"edge_cores"."id" IN (?)`, []interface{}{subquery}, nil
+}
+
+// followSQL returns the condition under which the edge row edge is followed
+// from the node whose id is in the column node, together with its vars, and
+// the column holding the node the edge leads to. Edges of symmetric kinds are
+// followed from either endpoint.
+func followSQL(edge, node string, direction Direction, symmetric []string) (string, []interface{}, string, error) {
+	source, target := edge+`."source_id"`, edge+`."target_id"`
+	var from, to string
+	switch direction {
+	case DirectionOutgoing:
+		from, to = source, target
+	case DirectionIncoming:
+		from, to = target, source
+	case DirectionBoth:
+		return `(` + source + ` = ` + node + ` OR ` + target + ` = ` + node + `)`, nil,
+			`CASE WHEN ` + source + ` = ` + node + ` THEN ` + target + ` ELSE ` + source + ` END`, nil
+	default:
+		return "", nil, "", NewUnsupportedDirectionError(direction)
+	}
+
+	if len(symmetric) == 0 {
+		return from + ` = ` + node, nil, to, nil
+	}
+	return `(` + from + ` = ` + node + ` OR (` + to + ` = ` + node + ` AND ` + edge + `."kind" IN ?))`, []interface{}{symmetric},
+		`CASE WHEN ` + from + ` = ` + node + ` THEN ` + to + ` ELSE ` + from + ` END`, nil
+}
+
+// reachableEdges returns every edge matching filter that leaves a node
+// reachable from startId in the given direction. A maxDepth above zero only
+// returns edges leaving nodes closer than maxDepth hops to the start. The
+// recursive part uses UNION, so cycles in the graph end the recursion.
+func reachableEdges(repository *Repository, startId string, direction Direction, filter edgeFilter, maxDepth int) ([]*EdgeCore, error) {
+	condition, filterVars, err := filter.condition(repository)
+	if err != nil {
+		return nil, err
+	}
+	join, joinVars, next, err := followSQL(`"edge_cores"`, `"reachable"."id"`, direction, repository.edgeSchemas.symmetricKinds())
+	if err != nil {
+		return nil, err
+	}
+
+	var sql string
+	vars := []interface{}{startId}
+	vars = append(vars, joinVars...)
+	reachable := `SELECT 1 FROM "reachable" WHERE ` + join
+	reachableVars := joinVars
+	if maxDepth > 0 {
+		sql = `WITH RECURSIVE "reachable" ("id", "depth") AS (` +
+			`SELECT "id", 0 FROM "node_cores" WHERE "id" = ? ` +
+			`UNION ` +
+			`SELECT ` + next + `, "reachable"."depth" + 1 FROM "edge_cores" JOIN "reachable" ON ` + join + ` ` +
+			`WHERE "reachable"."depth" < ? AND ` + condition + `) `
+		vars = append(vars, maxDepth)
+		reachable += ` AND "reachable"."depth" < ?`
+		reachableVars = append(reachableVars, maxDepth)
+	} else {
+		sql = `WITH RECURSIVE "reachable" ("id") AS (` +
+			`SELECT "id" FROM "node_cores" WHERE "id" = ? ` +
+			`UNION ` +
+			`SELECT ` + next + ` FROM "edge_cores" JOIN "reachable" ON ` + join + ` ` +
+			`WHERE ` + condition + `) `
+	}
+	vars = append(vars, filterVars...)
+
+	sql += `SELECT "edge_cores".* FROM "edge_cores" WHERE ` + condition + ` AND EXISTS (` + reachable + `)` +
+		` ORDER BY "edge_cores"."created_at", "edge_cores"."id"`
+	vars = append(vars, filterVars...)
+	vars = append(vars, reachableVars...)
+
+	var edges []*EdgeCore
+	if err := repository.db.Raw(sql, vars...).Find(&edges).Error; err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// edgesLeaving returns the edges matching filter that are followed from the
+// nodes with the given ids in the given direction, indexed by the node they
+// are followed from. Every id has an entry, which is empty for nodes without
+// such edges.
+func edgesLeaving(repository *Repository, ids []string, direction Direction, filter edgeFilter) (map[string][]*EdgeCore, error) {
+	condition, filterVars, err := filter.condition(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	source, target := `"edge_cores"."source_id" IN ?`, `"edge_cores"."target_id" IN ?`
+	var follow string
+	var vars []interface{}
+	switch direction {
+	case DirectionOutgoing:
+		follow, vars = source, []interface{}{ids}
+	case DirectionIncoming:
+		follow, vars = target, []interface{}{ids}
+		source, target = target, source
+	case DirectionBoth:
+		follow, vars = `(`+source+` OR `+target+`)`, []interface{}{ids, ids}
+	default:
+		return nil, NewUnsupportedDirectionError(direction)
+	}
+	if symmetric := repository.edgeSchemas.symmetricKinds(); direction != DirectionBoth && len(symmetric) > 0 {
+		follow = `(` + follow + ` OR (` + target + ` AND "edge_cores"."kind" IN ?))`
+		vars = append(vars, ids, symmetric)
+	}
+
+	var edges []*EdgeCore
+	err = repository.db.Model(&EdgeCore{}).
+		Where(follow, vars...).
+		Where(condition, filterVars...).
+		Order("created_at").
+		Order("id").
+		Find(&edges).Error
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := adjacency(edges, direction, repository.edgeSchemas)
+	result := make(map[string][]*EdgeCore, len(ids))
+	for _, id := range ids {
+		result[id] = indexed[id]
+	}
+	return result, nil
+}
+
+// adjacency indexes edges by the node they are followed from. Edges of
+// symmetric kinds are indexed under both endpoints.
+func adjacency(edges []*EdgeCore, direction Direction, schemas *EdgeSchemaRegistry) map[string][]*EdgeCore {
+	result := make(map[string][]*EdgeCore)
+	for _, edge := range edges {
+		from, to := edge.SourceId, edge.TargetId
+		if direction == DirectionIncoming {
+			from, to = to, from
+		}
+		result[from] = append(result[from], edge)
+		if to != from && (direction == DirectionBoth || schemas.IsSymmetric(edge.Kind)) {
+			result[to] = append(result[to], edge)
+		}
+	}
+	return result
+}
+
+// otherEndpoint returns the node an edge leads to when it is followed from
+// the node with the given id.
+func otherEndpoint(edge *EdgeCore, from string) string {
+	if edge.SourceId == from {
+		return edge.TargetId
+	}
+	return edge.SourceId
+}
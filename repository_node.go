@@ -42,11 +42,32 @@ func (scope *NodeScope[T]) SaveNode(model *T) (string, error) {
 
 	id := ensureNodeID(node)
 
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return "", err
+	}
+
 	err = scope.repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkParentCycle(tx, features, id, node.Core.ParentId); err != nil {
+			return err
+		}
+
+		if err := assignNodeRank(tx, &node.Core); err != nil {
+			return err
+		}
+
+		if err := ensureUniqueSiblingName(tx, features, &node.Core); err != nil {
+			return err
+		}
+
 		if err := tx.Save(&node.Core).Error; err != nil {
 			return err
 		}
 
+		if err := syncNodeClosure(tx, features, id, node.Core.ParentId); err != nil {
+			return err
+		}
+
 		if err := deleteNodeContents(tx, id); err != nil {
 			return err
 		}
@@ -94,19 +115,42 @@ func (scope *NodeScope[T]) SaveNode(model *T) (string, error) {
 	return id, err
 }
 
-// DeleteNode deletes the given node from the repository.
+// DeleteNode deletes the given node from the repository. Its children become
+// root nodes.
 func (scope *NodeScope[T]) DeleteNode(model *T) error {
+	_, err := scope.DeleteNodeWithPolicy(model, DeletePolicyOrphan)
+	return err
+}
+
+// DeleteNodeWithPolicy deletes the given node from the repository, applies
+// policy to its children and reports how many nodes and edges were removed.
+func (scope *NodeScope[T]) DeleteNodeWithPolicy(model *T, policy DeletePolicy) (*DeleteResult, error) {
 	if model == nil {
-		return NewNodeIsNilError()
+		return nil, NewNodeIsNilError()
 	}
 
 	node, err := nodeFromModel(scope.repository.adapters, model)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if node.Core.Id == "" {
+		return nil, gorm.ErrMissingWhereClause
 	}
-	return scope.repository.db.Transaction(func(tx *gorm.DB) error {
-		return tx.Delete(&node.Core).Error
+
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *DeleteResult
+	err = scope.repository.db.Transaction(func(tx *gorm.DB) error {
+		result, err = deleteNodes(tx, features, []string{node.Core.Id}, policy)
+		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (scope *NodeScope[T]) GetNode(id string) (*T, error) {
@@ -146,6 +190,44 @@ func (scope *NodeScope[T]) GetNode(id string) (*T, error) {
 	return modelFromNode[T](scope.repository.adapters, node)
 }
 
+// GetNodes returns the nodes with the given ids in the same order, loading
+// their relations in bulk. It fails with gorm.ErrRecordNotFound when any of
+// the nodes does not exist.
+func (scope *NodeScope[T]) GetNodes(ids []string) ([]*T, error) {
+	var cores []*NodeCore
+	if err := scope.repository.db.Where("id IN ?", ids).Find(&cores).Error; err != nil {
+		return nil, err
+	}
+	coresById := make(map[string]*NodeCore, len(cores))
+	for _, core := range cores {
+		coresById[core.Id] = core
+	}
+
+	ordered := make([]*NodeCore, 0, len(ids))
+	for _, id := range ids {
+		core, ok := coresById[id]
+		if !ok {
+			return nil, gorm.ErrRecordNotFound
+		}
+		ordered = append(ordered, core)
+	}
+
+	nodes, err := scope.repository.loadNodes(ordered, fetchOptions{kv: true, content: true, tags: true})
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]*T, 0, len(nodes))
+	for _, node := range nodes {
+		model, err := modelFromNode[T](scope.repository.adapters, node)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
 func ensureNodeID(node *Node) string {
 	if node.Core.Id == "" {
 		node.Core.Id = uuid.New().String()
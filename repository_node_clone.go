@@ -0,0 +1,189 @@
+package nod
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CloneSubtree copies the node with the given id and every node below it under
+// newParentId, or to the root level when newParentId is nil. KV, content and
+// tags are copied with each node, and edges between two copied nodes are
+// copied with their endpoints remapped. The copy of the root is placed after
+// the last child of newParentId; descendants keep their order. The whole copy
+// is made in a single transaction.
+func (scope *NodeScope[T]) CloneSubtree(rootId string, newParentId *string, opts CloneOptions) (*CloneResult, error) {
+	var result *CloneResult
+	err := scope.repository.Transaction(func(txRepository *Repository) error {
+		var root NodeCore
+		if err := txRepository.db.First(&root, "id = ?", rootId).Error; err != nil {
+			return err
+		}
+
+		features, err := txRepository.enabledFeatures()
+		if err != nil {
+			return err
+		}
+		ids, err := subtreeIds(txRepository.db, features, []string{rootId})
+		if err != nil {
+			return err
+		}
+
+		var cores []*NodeCore
+		if err := txRepository.db.Where("id IN ?", ids).Find(&cores).Error; err != nil {
+			return err
+		}
+		nodes, err := txRepository.loadNodes(parentsFirst(rootId, cores), fetchOptions{kv: true, content: true, tags: true})
+		if err != nil {
+			return err
+		}
+
+		result = &CloneResult{
+			NodeIds: make(map[string]string, len(nodes)),
+			EdgeIds: map[string]string{},
+		}
+		for _, node := range nodes {
+			result.NodeIds[node.Core.Id] = uuid.New().String()
+		}
+		result.RootId = result.NodeIds[rootId]
+
+		for _, node := range nodes {
+			parentId := newParentId
+			if node.Core.Id != rootId {
+				parentId = Ptr(result.NodeIds[*node.Core.ParentId])
+			}
+			if err := cloneNode(txRepository, node, result.NodeIds[node.Core.Id], parentId, node.Core.Id == rootId, opts); err != nil {
+				return err
+			}
+		}
+
+		var edgeCores []*EdgeCore
+		err = txRepository.db.
+			Where("source_id IN ? AND target_id IN ?", ids, ids).
+			Order("created_at").
+			Order("id").
+			Find(&edgeCores).Error
+		if err != nil {
+			return err
+		}
+		edges, err := txRepository.loadEdges(edgeCores, fetchOptions{kv: true, content: true, tags: true})
+		if err != nil {
+			return err
+		}
+		for _, edge := range edges {
+			id, err := cloneEdge(txRepository, edge, result.NodeIds, opts)
+			if err != nil {
+				return err
+			}
+			result.EdgeIds[edge.Core.Id] = id
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parentsFirst orders the cores of a subtree so that every node follows its
+// parent, which lets the copies be saved one after another.
+func parentsFirst(rootId string, cores []*NodeCore) []*NodeCore {
+	children := make(map[string][]*NodeCore, len(cores))
+	var ordered []*NodeCore
+	for _, core := range cores {
+		if core.Id == rootId {
+			ordered = append(ordered, core)
+		} else if core.ParentId != nil {
+			children[*core.ParentId] = append(children[*core.ParentId], core)
+		}
+	}
+
+	for i := 0; i < len(ordered); i++ {
+		ordered = append(ordered, children[ordered[i].Id]...)
+	}
+	return ordered
+}
+
+// cloneNode saves a copy of node with the given id under parentId. The copy of
+// the subtree root gets a new rank, and the name from opts when one is set.
+func cloneNode(txRepository *Repository, node *Node, id string, parentId *string, root bool, opts CloneOptions) error {
+	copied := &Node{
+		Core: NodeCore{
+			Id:          id,
+			NamespaceId: node.Core.NamespaceId,
+			ParentId:    parentId,
+			Rank:        node.Core.Rank,
+			Kind:        node.Core.Kind,
+			Status:      node.Core.Status,
+			Name:        node.Core.Name,
+		},
+		KV:      make(map[string]*NodeKV, len(node.KV)),
+		Content: make(map[string]*NodeContent, len(node.Content)),
+	}
+	if opts.NamespaceId != nil {
+		copied.Core.NamespaceId = opts.NamespaceId
+	}
+
+	if root {
+		copied.Core.Rank = ""
+		if opts.Name != "" {
+			copied.Core.Name = opts.Name
+		}
+	}
+
+	for key, value := range node.KV {
+		kv := *value
+		kv.Node = nil
+		copied.KV[key] = &kv
+	}
+	for key, value := range node.Content {
+		content := *value
+		content.Node = nil
+		content.CreatedAt = time.Time{}
+		content.UpdatedAt = time.Time{}
+		copied.Content[key] = &content
+	}
+	for _, tag := range node.Tags {
+		copied.Tags = append(copied.Tags, &Tag{Name: tag.Name})
+	}
+
+	_, err := txRepository.Nodes().SaveNode(copied)
+	return err
+}
+
+func cloneEdge(txRepository *Repository, edge *Edge, nodeIds map[string]string, opts CloneOptions) (string, error) {
+	copied := &Edge{
+		Core: EdgeCore{
+			Id:          uuid.New().String(),
+			NamespaceId: edge.Core.NamespaceId,
+			SourceId:    nodeIds[edge.Core.SourceId],
+			TargetId:    nodeIds[edge.Core.TargetId],
+			Name:        edge.Core.Name,
+			Kind:        edge.Core.Kind,
+			Status:      edge.Core.Status,
+		},
+		KV:      make(map[string]*EdgeKV, len(edge.KV)),
+		Content: make(map[string]*EdgeContent, len(edge.Content)),
+	}
+	if opts.NamespaceId != nil {
+		copied.Core.NamespaceId = opts.NamespaceId
+	}
+
+	for key, value := range edge.KV {
+		kv := *value
+		kv.Edge = nil
+		copied.KV[key] = &kv
+	}
+	for key, value := range edge.Content {
+		content := *value
+		content.Edge = nil
+		content.CreatedAt = time.Time{}
+		content.UpdatedAt = time.Time{}
+		copied.Content[key] = &content
+	}
+	for _, tag := range edge.Tags {
+		copied.Tags = append(copied.Tags, &Tag{Name: tag.Name})
+	}
+
+	return txRepository.Edges().SaveEdge(copied)
+}
@@ -0,0 +1,233 @@
+package nod
+
+import (
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// deleteBatchSize is the number of ids bound per query while nodes are
+// deleted, which keeps large deletes below the database's variable limit even
+// when a query binds the ids twice.
+const deleteBatchSize = 400
+
+// subtreeCTE defines node_tree as the nodes bound to the first placeholder
+// together with all of their descendants.
+const subtreeCTE = `WITH RECURSIVE "node_tree" ("id", "depth") AS (` +
+	`SELECT "id", 0 FROM "node_cores" WHERE "id" IN ? ` +
+	`UNION ALL ` +
+	`SELECT "node_cores"."id", "node_tree"."depth" + 1 FROM "node_cores" ` +
+	`JOIN "node_tree" ON "node_cores"."parent_id" = "node_tree"."id" ` +
+	`WHERE "node_tree"."depth" < ?)`
+
+// deleteNodes deletes the nodes with the given ids and applies policy to
+// their children. Edges, KV, content and tag bindings of deleted nodes are
+// removed by the foreign key cascades.
+func deleteNodes(tx *gorm.DB, features schemaFeatures, ids []string, policy DeletePolicy) (*DeleteResult, error) {
+	result := &DeleteResult{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var err error
+	switch policy {
+	case DeletePolicyOrphan:
+		err = detachChildren(tx, features, ids)
+	case DeletePolicyCascade:
+		ids, err = subtreeIds(tx, features, ids)
+	case DeletePolicyReparent:
+		err = reparentChildren(tx, features, ids)
+	case DeletePolicyRestrict:
+		err = ensureNoChildren(tx, ids)
+	default:
+		return nil, NewUnsupportedDeletePolicyError(policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make(map[string]bool)
+	for batch := range slices.Chunk(ids, deleteBatchSize) {
+		var edgeIds []string
+		err := tx.Model(&EdgeCore{}).
+			Where("source_id IN ? OR target_id IN ?", batch, batch).
+			Pluck("id", &edgeIds).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range edgeIds {
+			edges[id] = true
+		}
+	}
+	result.Edges = int64(len(edges))
+
+	for batch := range slices.Chunk(ids, deleteBatchSize) {
+		deleted := tx.Where("id IN ?", batch).Delete(&NodeCore{})
+		if deleted.Error != nil {
+			return nil, deleted.Error
+		}
+		result.Nodes += deleted.RowsAffected
+	}
+
+	return result, nil
+}
+
+func subtreeIds(tx *gorm.DB, features schemaFeatures, ids []string) ([]string, error) {
+	var subtree []string
+	for batch := range slices.Chunk(ids, deleteBatchSize) {
+		var descendants []string
+		var err error
+		if features.closure {
+			err = tx.Model(&NodeClosure{}).Distinct("descendant_id").Where("ancestor_id IN ?", batch).Scan(&descendants).Error
+		} else {
+			err = tx.Raw(subtreeCTE+` SELECT DISTINCT "id" FROM "node_tree"`, batch, hierarchyDepthLimit).Scan(&descendants).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+		subtree = append(subtree, descendants...)
+	}
+	return uniqueStrings(subtree), nil
+}
+
+// survivingChildren returns the children of the given nodes that are not
+// deleted themselves, in rank order per parent.
+func survivingChildren(tx *gorm.DB, ids []string) ([]*NodeCore, error) {
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	var survivors []*NodeCore
+	for batch := range slices.Chunk(ids, deleteBatchSize) {
+		var children []*NodeCore
+		err := tx.Select("id", "parent_id", "namespace_id", "name").
+			Where("parent_id IN ?", batch).
+			Order("rank").
+			Order("name").
+			Order("id").
+			Find(&children).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if !deleted[child.Id] {
+				survivors = append(survivors, child)
+			}
+		}
+	}
+	return survivors, nil
+}
+
+// detachChildren unlinks the surviving children of the given nodes from the
+// closure table before the foreign key turns them into root nodes.
+func detachChildren(tx *gorm.DB, features schemaFeatures, ids []string) error {
+	if !features.closure {
+		return nil
+	}
+
+	children, err := survivingChildren(tx, ids)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := syncNodeClosure(tx, features, child.Id, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reparentChildren moves the surviving children of the given nodes to the
+// nearest ancestor that is not deleted as well. Moved children keep their
+// order and are placed after the children the ancestor already has. Children
+// whose deleted ancestors form a cycle become root nodes of their own
+// namespace.
+func reparentChildren(tx *gorm.DB, features schemaFeatures, ids []string) error {
+	parents := make(map[string]*string, len(ids))
+	for batch := range slices.Chunk(ids, deleteBatchSize) {
+		var cores []*NodeCore
+		if err := tx.Select("id", "parent_id").Where("id IN ?", batch).Find(&cores).Error; err != nil {
+			return err
+		}
+		for _, core := range cores {
+			parents[core.Id] = core.ParentId
+		}
+	}
+
+	children, err := survivingChildren(tx, ids)
+	if err != nil {
+		return err
+	}
+
+	// Children that become root nodes are ranked per namespace, as children
+	// of one parent may belong to different namespaces and root nodes are
+	// grouped by namespace.
+	type movedGroup struct {
+		deletedId   string
+		namespaceId string
+	}
+	groups := make(map[movedGroup][]*NodeCore)
+	var order []movedGroup
+	for _, child := range children {
+		group := movedGroup{deletedId: *child.ParentId}
+		if survivingAncestor(parents, group.deletedId) == nil && child.NamespaceId != nil {
+			group.namespaceId = *child.NamespaceId
+		}
+		if _, seen := groups[group]; !seen {
+			order = append(order, group)
+		}
+		groups[group] = append(groups[group], child)
+	}
+
+	for _, group := range order {
+		moved := groups[group]
+		parentId := survivingAncestor(parents, group.deletedId)
+		last, err := lastSiblingRank(tx, parentId, moved[0].NamespaceId, "")
+		if err != nil {
+			return err
+		}
+		ranks := ranksBetween(last, "", len(moved))
+		for i, child := range moved {
+			child.ParentId = parentId
+			if err := ensureUniqueSiblingName(tx, features, child); err != nil {
+				return err
+			}
+			if err := updateNodePlacement(tx, features, child.Id, parentId, ranks[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// survivingAncestor walks up from the deleted node with the given id to the
+// first ancestor that is not deleted. It returns nil for deleted nodes without
+// one, and when the parent references of deleted nodes form a cycle.
+func survivingAncestor(parents map[string]*string, id string) *string {
+	seen := map[string]bool{id: true}
+	parentId := parents[id]
+	for parentId != nil {
+		next, deleted := parents[*parentId]
+		if !deleted {
+			return parentId
+		}
+		if seen[*parentId] {
+			return nil
+		}
+		seen[*parentId] = true
+		parentId = next
+	}
+	return nil
+}
+
+func ensureNoChildren(tx *gorm.DB, ids []string) error {
+	children, err := survivingChildren(tx, ids)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return NewNodeHasChildrenError(*children[0].ParentId)
+	}
+	return nil
+}
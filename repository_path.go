@@ -0,0 +1,274 @@
+package nod
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// pathBatchSize is the number of nodes whose edges are read per query while a
+// path is searched.
+const pathBatchSize = 100
+
+// ShortestPath returns the path from the node with fromId to the node with
+// toId that has the lowest cost. Without a weight key this is the path with
+// the fewest edges, found breadth first; with one, Dijkstra's algorithm sums
+// the edge weights. The graph is read frontier by frontier and the search
+// stops once toId is reached, so only the part of the graph closer to fromId
+// than toId is read. Unconnected nodes fail with NoPathError, missing nodes
+// with gorm.ErrRecordNotFound and weights that are not numbers with
+// InvalidEdgeWeightError.
+func (r *Repository) ShortestPath(fromId, toId string, opts PathOptions) (*Path, error) {
+	for _, id := range []string{fromId, toId} {
+		if err := r.db.Select("id").First(&NodeCore{}, "id = ?", id).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	graph := &pathGraph{
+		repository: r,
+		direction:  opts.Direction,
+		filter:     edgeFilter{kinds: opts.Kinds, where: opts.Where},
+		weightKey:  opts.WeightKey,
+		edges:      make(map[string][]*EdgeCore),
+		weights:    make(map[string]float64),
+	}
+	var previous map[string]*EdgeCore
+	var cost float64
+	var err error
+	if opts.WeightKey == "" {
+		previous, cost, err = graph.breadthFirstPath(fromId, toId)
+	} else {
+		previous, cost, err = graph.weightedPath(fromId, toId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, found := previous[toId]; !found {
+		return nil, NewNoPathError(fromId, toId)
+	}
+
+	nodeIds := []string{toId}
+	var edgeCores []*EdgeCore
+	for current := toId; current != fromId; {
+		edge := previous[current]
+		current = otherEndpoint(edge, current)
+		edgeCores = append(edgeCores, edge)
+		nodeIds = append(nodeIds, current)
+	}
+	slices.Reverse(nodeIds)
+	slices.Reverse(edgeCores)
+
+	return r.loadPath(nodeIds, edgeCores, cost)
+}
+
+// pathGraph reads the edges a path search follows as the search reaches
+// their nodes, together with their weights.
+type pathGraph struct {
+	repository *Repository
+	direction  Direction
+	filter     edgeFilter
+	weightKey  string
+	edges      map[string][]*EdgeCore
+	weights    map[string]float64
+}
+
+// load reads the edges leaving the given nodes, and their weights when the
+// search is weighted. Nodes whose edges were read before are skipped.
+func (g *pathGraph) load(ids []string) error {
+	ids = slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+		_, loaded := g.edges[id]
+		return loaded
+	})
+	for batch := range slices.Chunk(ids, pathBatchSize) {
+		leaving, err := edgesLeaving(g.repository, batch, g.direction, g.filter)
+		if err != nil {
+			return err
+		}
+		var edgeIds []string
+		for id, edges := range leaving {
+			g.edges[id] = edges
+			for _, edge := range edges {
+				edgeIds = append(edgeIds, edge.Id)
+			}
+		}
+		if g.weightKey != "" && len(edgeIds) > 0 {
+			if err := g.loadWeights(edgeIds); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadWeights reads the numeric KV named by the weight key of the given
+// edges. Edges without the KV keep the default weight of 1. Negative weights
+// fail with NegativeEdgeWeightError and values that are not numbers with
+// InvalidEdgeWeightError.
+func (g *pathGraph) loadWeights(edgeIds []string) error {
+	var kvs []*EdgeKV
+	if err := g.repository.db.Where("key = ? AND edge_id IN ?", g.weightKey, edgeIds).Find(&kvs).Error; err != nil {
+		return err
+	}
+	for _, kv := range kvs {
+		weight, ok := edgeKVNumber(kv)
+		if !ok {
+			return NewInvalidEdgeWeightError(kv.EdgeId, g.weightKey)
+		}
+		if weight < 0 {
+			return NewNegativeEdgeWeightError(kv.EdgeId, weight)
+		}
+		g.weights[kv.EdgeId] = weight
+	}
+	return nil
+}
+
+func (g *pathGraph) weight(edge *EdgeCore) float64 {
+	if weight, ok := g.weights[edge.Id]; ok {
+		return weight
+	}
+	return 1
+}
+
+// breadthFirstPath records the edge each node was first reached by, reading
+// the graph level by level until toId is reached. The start node is recorded
+// with a nil edge.
+func (g *pathGraph) breadthFirstPath(fromId, toId string) (map[string]*EdgeCore, float64, error) {
+	previous := map[string]*EdgeCore{fromId: nil}
+	level := []string{fromId}
+	for depth := 0; len(level) > 0; depth++ {
+		if slices.Contains(level, toId) {
+			return previous, float64(depth), nil
+		}
+		if err := g.load(level); err != nil {
+			return nil, 0, err
+		}
+		var next []string
+		for _, current := range level {
+			for _, edge := range g.edges[current] {
+				to := otherEndpoint(edge, current)
+				if _, seen := previous[to]; seen {
+					continue
+				}
+				previous[to] = edge
+				next = append(next, to)
+			}
+		}
+		level = next
+	}
+	return previous, 0, nil
+}
+
+// weightedPath runs Dijkstra's algorithm from fromId until toId is settled.
+// The edges of a node are read when it is settled, together with those of
+// the other queued nodes whose edges were not read yet.
+func (g *pathGraph) weightedPath(fromId, toId string) (map[string]*EdgeCore, float64, error) {
+	previous := map[string]*EdgeCore{fromId: nil}
+	costs := map[string]float64{fromId: 0}
+	settled := map[string]bool{}
+	queue := &pathQueue{{nodeId: fromId}}
+	for sequence := 1; queue.Len() > 0; {
+		current := heap.Pop(queue).(pathQueueItem)
+		if settled[current.nodeId] {
+			continue
+		}
+		settled[current.nodeId] = true
+		if current.nodeId == toId {
+			return previous, current.cost, nil
+		}
+
+		if _, loaded := g.edges[current.nodeId]; !loaded {
+			ids := []string{current.nodeId}
+			for _, item := range *queue {
+				if !settled[item.nodeId] && len(ids) < pathBatchSize {
+					ids = append(ids, item.nodeId)
+				}
+			}
+			if err := g.load(ids); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		for _, edge := range g.edges[current.nodeId] {
+			next := otherEndpoint(edge, current.nodeId)
+			cost := current.cost + g.weight(edge)
+			if known, seen := costs[next]; seen && known <= cost {
+				continue
+			}
+			costs[next] = cost
+			previous[next] = edge
+			heap.Push(queue, pathQueueItem{nodeId: next, cost: cost, sequence: sequence})
+			sequence++
+		}
+	}
+	return previous, 0, nil
+}
+
+func edgeKVNumber(kv *EdgeKV) (float64, bool) {
+	switch {
+	case kv.ValueNumber != nil:
+		return *kv.ValueNumber, true
+	case kv.ValueInt != nil:
+		return float64(*kv.ValueInt), true
+	case kv.ValueInt64 != nil:
+		return float64(*kv.ValueInt64), true
+	default:
+		return 0, false
+	}
+}
+
+// loadPath loads the nodes and edges of a path with all of their relations.
+func (r *Repository) loadPath(nodeIds []string, edgeCores []*EdgeCore, cost float64) (*Path, error) {
+	var cores []*NodeCore
+	if err := r.db.Where("id IN ?", nodeIds).Find(&cores).Error; err != nil {
+		return nil, err
+	}
+	coresById := make(map[string]*NodeCore, len(cores))
+	for _, core := range cores {
+		coresById[core.Id] = core
+	}
+	ordered := make([]*NodeCore, 0, len(nodeIds))
+	for _, id := range nodeIds {
+		ordered = append(ordered, coresById[id])
+	}
+
+	fetch := fetchOptions{kv: true, content: true, tags: true}
+	nodes, err := r.loadNodes(ordered, fetch)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := r.loadEdges(edgeCores, fetch)
+	if err != nil {
+		return nil, err
+	}
+	return &Path{Nodes: nodes, Edges: edges, Cost: cost}, nil
+}
+
+type pathQueueItem struct {
+	nodeId   string
+	cost     float64
+	sequence int
+}
+
+// pathQueue is a min-heap of nodes by cost. Ties are broken by insertion
+// order, which keeps the chosen path deterministic.
+type pathQueue []pathQueueItem
+
+func (q pathQueue) Len() int { return len(q) }
+
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *pathQueue) Push(item any) { *q = append(*q, item.(pathQueueItem)) }
+
+func (q *pathQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,227 @@
+package nod
+
+import (
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// patternColumn is a named node or edge returned by a pattern query.
+type patternColumn struct {
+	alias string
+	edge  bool
+}
+
+// FindAll returns every distinct binding of the named nodes and edges of the
+// pattern, ordered by their ids. Within one match every edge is a different
+// edge, while unnamed nodes may repeat.
+func (q *PatternQuery) FindAll() ([]*PatternMatch, error) {
+	sql, vars, columns, err := q.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.repository.db.Raw(sql, vars...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tuples [][]string
+	for rows.Next() {
+		tuple := make([]string, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range tuple {
+			targets[i] = &tuple[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, tuple)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var nodeIds, edgeIds []string
+	for _, tuple := range tuples {
+		for i, column := range columns {
+			if column.edge {
+				edgeIds = append(edgeIds, tuple[i])
+			} else {
+				nodeIds = append(nodeIds, tuple[i])
+			}
+		}
+	}
+	nodes, err := q.loadNodes(nodeIds)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := q.loadEdges(edgeIds)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*PatternMatch, 0, len(tuples))
+	for _, tuple := range tuples {
+		match := &PatternMatch{Nodes: map[string]*Node{}, Edges: map[string]*Edge{}}
+		for i, column := range columns {
+			if column.edge {
+				match.Edges[column.alias] = edges[tuple[i]]
+			} else {
+				match.Nodes[column.alias] = nodes[tuple[i]]
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// compile joins one node_cores row per node and one edge_cores row per edge of
+// the pattern. Node and edge expressions are applied through nested node_cores
+// and edge_cores subqueries, so they compile exactly as in NodeQuery and
+// EdgeQuery.
+func (q *PatternQuery) compile() (string, []interface{}, []patternColumn, error) {
+	if len(q.elements)%2 == 0 {
+		return "", nil, nil, NewInvalidPatternError("a pattern alternates nodes and edges and starts and ends with a node")
+	}
+	for i, element := range q.elements {
+		if element.edge != (i%2 == 1) {
+			return "", nil, nil, NewInvalidPatternError("a pattern alternates nodes and edges and starts and ends with a node")
+		}
+	}
+
+	table := func(i int) string {
+		if q.elements[i].edge {
+			return `"e` + strconv.Itoa(i/2) + `"`
+		}
+		return `"n` + strconv.Itoa(i/2) + `"`
+	}
+
+	var conditions []string
+	var conditionVars []interface{}
+	var columns []patternColumn
+	var selected []string
+	aliases := map[string]int{}
+	wheres := make([][]Expression, len(q.elements))
+	for i, element := range q.elements {
+		if !isNilValue(element.where) {
+			wheres[i] = append(wheres[i], element.where)
+		}
+		if element.alias == "" {
+			continue
+		}
+
+		first, seen := aliases[element.alias]
+		switch {
+		case !seen:
+			aliases[element.alias] = i
+			columns = append(columns, patternColumn{alias: element.alias, edge: element.edge})
+			selected = append(selected, table(i)+`."id"`)
+		case element.edge || q.elements[first].edge:
+			return "", nil, nil, NewInvalidPatternError("alias " + element.alias + " binds an edge more than once")
+		default:
+			conditions = append(conditions, table(i)+`."id" = `+table(first)+`."id"`)
+		}
+	}
+	if len(columns) == 0 {
+		return "", nil, nil, NewInvalidPatternError("a pattern needs at least one alias")
+	}
+	for _, where := range q.where {
+		i, ok := aliases[where.alias]
+		if !ok {
+			return "", nil, nil, NewInvalidPatternError("unknown alias " + where.alias)
+		}
+		if !isNilValue(where.expr) {
+			wheres[i] = append(wheres[i], where.expr)
+		}
+	}
+
+	symmetric := q.repository.edgeSchemas.symmetricKinds()
+	from := ` FROM "node_cores" AS ` + table(0)
+	var vars []interface{}
+	for i := 1; i < len(q.elements); i += 2 {
+		follow, followVars, next, err := followSQL(table(i), table(i-1)+`."id"`, q.elements[i].direction, symmetric)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		from += ` JOIN "edge_cores" AS ` + table(i) + ` ON ` + follow +
+			` JOIN "node_cores" AS ` + table(i+1) + ` ON ` + table(i+1) + `."id" = ` + next
+		vars = append(vars, followVars...)
+	}
+
+	for i, exprs := range wheres {
+		if len(exprs) == 0 {
+			continue
+		}
+		subquery, err := q.subquery(q.elements[i].edge, And(exprs...))
+		if err != nil {
+			return "", nil, nil, err
+		}
+		conditions = append(conditions, table(i)+`."id" IN (?)`)
+		conditionVars = append(conditionVars, subquery)
+	}
+	for i := 1; i < len(q.elements); i += 2 {
+		for j := i + 2; j < len(q.elements); j += 2 {
+			conditions = append(conditions, table(i)+`."id" <> `+table(j)+`."id"`)
+		}
+	}
+
+	sql := `SELECT DISTINCT ` + strings.Join(selected, ", ") + from
+	if len(conditions) > 0 {
+		sql += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	sql += ` ORDER BY ` + strings.Join(selected, ", ")
+	vars = append(vars, conditionVars...)
+	if q.limit > 0 {
+		sql += ` LIMIT ?`
+		vars = append(vars, q.limit)
+	}
+	return sql, vars, columns, nil
+}
+
+// subquery selects the ids of the nodes, or edges, matching expr.
+func (q *PatternQuery) subquery(edge bool, expr Expression) (*gorm.DB, error) {
+	db := q.repository.db.Session(&gorm.Session{NewDB: true})
+	if edge {
+		return applyExpression(db.Model(&EdgeCore{}).Select("edge_cores.id"), expr, ScopeEdge, q.repository)
+	}
+	return applyExpression(db.Model(&NodeCore{}).Select("node_cores.id"), expr, ScopeNode, q.repository)
+}
+
+func (q *PatternQuery) loadNodes(ids []string) (map[string]*Node, error) {
+	var cores []*NodeCore
+	if len(ids) > 0 {
+		if err := q.repository.db.Where("id IN ?", uniqueStrings(ids)).Find(&cores).Error; err != nil {
+			return nil, err
+		}
+	}
+	nodes, err := q.repository.loadNodes(cores, q.fetch)
+	if err != nil {
+		return nil, err
+	}
+	nodesById := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		nodesById[node.Core.Id] = node
+	}
+	return nodesById, nil
+}
+
+func (q *PatternQuery) loadEdges(ids []string) (map[string]*Edge, error) {
+	var cores []*EdgeCore
+	if len(ids) > 0 {
+		if err := q.repository.db.Where("id IN ?", uniqueStrings(ids)).Find(&cores).Error; err != nil {
+			return nil, err
+		}
+	}
+	edges, err := q.repository.loadEdges(cores, q.fetch)
+	if err != nil {
+		return nil, err
+	}
+	edgesById := make(map[string]*Edge, len(edges))
+	for _, edge := range edges {
+		edgesById[edge.Core.Id] = edge
+	}
+	return edgesById, nil
+}
@@ -0,0 +1,122 @@
+package nod
+
+import "slices"
+
+// loadEdgeEndpoints sets the source nodes of edges, or their target nodes
+// when target is true, loading each endpoint once.
+func (r *Repository) loadEdgeEndpoints(edges []*Edge, target bool, fetch fetchOptions) error {
+	endpoint := func(edge *Edge) string {
+		if target {
+			return edge.Core.TargetId
+		}
+		return edge.Core.SourceId
+	}
+
+	seen := make(map[string]bool, len(edges))
+	ids := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		if id := endpoint(edge); !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var cores []*NodeCore
+	if err := r.db.Where("id IN ?", ids).Find(&cores).Error; err != nil {
+		return err
+	}
+	nodes, err := r.loadNodes(cores, fetch)
+	if err != nil {
+		return err
+	}
+	nodesById := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		nodesById[node.Core.Id] = node
+	}
+
+	for _, edge := range edges {
+		if target {
+			edge.Target = nodesById[edge.Core.TargetId]
+		} else {
+			edge.Source = nodesById[edge.Core.SourceId]
+		}
+	}
+	return nil
+}
+
+// loadNodeEdges sets the outgoing or incoming edges of nodes, in creation
+// order, with a single query for all nodes. Edges of symmetric kinds are set
+// on both endpoints.
+func (r *Repository) loadNodeEdges(nodes []*Node, direction Direction, preload *edgePreload) error {
+	var column, other string
+	switch direction {
+	case DirectionOutgoing:
+		column, other = "source_id", "target_id"
+	case DirectionIncoming:
+		column, other = "target_id", "source_id"
+	default:
+		return NewUnsupportedDirectionError(direction)
+	}
+
+	nodeIds := make([]string, 0, len(nodes))
+	loaded := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		nodeIds = append(nodeIds, node.Core.Id)
+		loaded[node.Core.Id] = true
+	}
+
+	var symmetric []string
+	for _, kind := range r.edgeSchemas.symmetricKinds() {
+		if preload.allKinds || slices.Contains(preload.kinds, kind) {
+			symmetric = append(symmetric, kind)
+		}
+	}
+
+	var cores []*EdgeCore
+	if len(nodeIds) > 0 {
+		db := r.db.Where(column+" IN ?", nodeIds)
+		if len(symmetric) > 0 {
+			db = r.db.Where(db.Or(other+" IN ? AND kind IN ?", nodeIds, symmetric))
+		}
+		if !preload.allKinds {
+			db = db.Where("kind IN ?", preload.kinds)
+		}
+		if err := db.Order("created_at").Order("id").Find(&cores).Error; err != nil {
+			return err
+		}
+	}
+
+	edges, err := r.loadEdges(cores, preload.fetch)
+	if err != nil {
+		return err
+	}
+	edgesByNode := make(map[string][]*Edge, len(nodes))
+	for _, edge := range edges {
+		from, to := edge.Core.SourceId, edge.Core.TargetId
+		if direction == DirectionIncoming {
+			from, to = to, from
+		}
+		if loaded[from] {
+			edgesByNode[from] = append(edgesByNode[from], edge)
+		}
+		if to != from && loaded[to] && r.edgeSchemas.IsSymmetric(edge.Core.Kind) {
+			edgesByNode[to] = append(edgesByNode[to], edge)
+		}
+	}
+
+	for _, node := range nodes {
+		nodeEdges := edgesByNode[node.Core.Id]
+		if nodeEdges == nil {
+			nodeEdges = []*Edge{}
+		}
+		if direction == DirectionOutgoing {
+			node.Outgoing = nodeEdges
+		} else {
+			node.Incoming = nodeEdges
+		}
+	}
+	return nil
+}
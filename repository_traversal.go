@@ -0,0 +1,313 @@
+package nod
+
+// traversalBatchSize is the number of visited nodes loaded per query while a
+// traversal is streamed.
+const traversalBatchSize = 100
+
+// Traversal walks the graph of edges starting at a single node. Configure it
+// with the builder methods and run it with Each or FindAll.
+type Traversal struct {
+	repository *Repository
+	startId    string
+	direction  Direction
+	order      TraversalOrder
+	filter     edgeFilter
+	maxDepth   int
+	limit      int
+	fetch      fetchOptions
+}
+
+type traversalVisit struct {
+	nodeId string
+	edge   *EdgeCore
+	depth  int
+}
+
+// Traverse creates a breadth-first traversal of outgoing edges starting at the
+// node with the given id.
+func (r *Repository) Traverse(startId string) *Traversal {
+	return &Traversal{
+		repository: r,
+		startId:    startId,
+	}
+}
+
+// Direction selects which edges the traversal follows.
+func (t *Traversal) Direction(direction Direction) *Traversal {
+	t.direction = direction
+	return t
+}
+
+// Order selects breadth-first or depth-first visiting order.
+func (t *Traversal) Order(order TraversalOrder) *Traversal {
+	t.order = order
+	return t
+}
+
+// Kinds restricts the traversal to edges of the given kinds.
+func (t *Traversal) Kinds(kinds ...string) *Traversal {
+	t.filter.addKinds(kinds)
+	return t
+}
+
+// Where restricts the traversal to edges matching the edge expression.
+func (t *Traversal) Where(expr Expression) *Traversal {
+	t.filter.addWhere(expr)
+	return t
+}
+
+// MaxDepth stops the traversal the given number of hops away from the start
+// node. Zero or less walks every reachable node.
+func (t *Traversal) MaxDepth(depth int) *Traversal {
+	t.maxDepth = depth
+	return t
+}
+
+// Limit stops the traversal after the given number of visited nodes, the start
+// node included. Zero or less visits every reachable node.
+func (t *Traversal) Limit(limit int) *Traversal {
+	t.limit = limit
+	return t
+}
+
+// WithKV loads the KV values of visited nodes and of the edges that reached them.
+func (t *Traversal) WithKV() *Traversal {
+	t.fetch.kv = true
+	return t
+}
+
+// WithContent loads the content of visited nodes and of the edges that reached them.
+func (t *Traversal) WithContent() *Traversal {
+	t.fetch.content = true
+	return t
+}
+
+// WithTags loads the tags of visited nodes and of the edges that reached them.
+func (t *Traversal) WithTags() *Traversal {
+	t.fetch.tags = true
+	return t
+}
+
+// Each calls fn for every visited node in visiting order until fn returns
+// false. The edges the traversal may follow are read with one recursive query,
+// bounded by the max depth and the limit, and visited nodes are loaded in
+// batches while steps are streamed, so an early stop also ends the node reads.
+// A missing start node fails with gorm.ErrRecordNotFound.
+func (t *Traversal) Each(fn func(step *TraversalStep) bool) error {
+	var start NodeCore
+	if err := t.repository.db.Select("id").First(&start, "id = ?", t.startId).Error; err != nil {
+		return err
+	}
+	if t.order != BreadthFirst && t.order != DepthFirst {
+		return NewUnsupportedTraversalOrderError(t.order)
+	}
+	edges, err := t.edges()
+	if err != nil {
+		return err
+	}
+
+	stream := &traversalStream{traversal: t, fn: fn}
+	if t.order == DepthFirst {
+		err = t.depthFirst(stream, edges)
+	} else {
+		err = t.breadthFirst(stream, edges)
+	}
+	if err != nil {
+		return err
+	}
+	return stream.flush()
+}
+
+// FindAll returns every visited node in visiting order.
+func (t *Traversal) FindAll() ([]*TraversalStep, error) {
+	var steps []*TraversalStep
+	err := t.Each(func(step *TraversalStep) bool {
+		steps = append(steps, step)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// edges reads the edges the traversal may follow with a recursive query and
+// indexes them by the node they are followed from. Every visit is at most one
+// hop deeper than the visits before it, so a limit also bounds the depth the
+// query descends to.
+func (t *Traversal) edges() (map[string][]*EdgeCore, error) {
+	maxDepth := t.maxDepth
+	if t.limit > 0 && (maxDepth <= 0 || t.limit-1 < maxDepth) {
+		maxDepth = t.limit - 1
+		if maxDepth == 0 {
+			return nil, nil
+		}
+	}
+	edges, err := reachableEdges(t.repository, t.startId, t.direction, t.filter, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	return adjacency(edges, t.direction, t.repository.edgeSchemas), nil
+}
+
+// breadthFirst visits the graph level by level. Every node is visited once,
+// through the first edge that reaches it.
+func (t *Traversal) breadthFirst(stream *traversalStream, edges map[string][]*EdgeCore) error {
+	visited := map[string]bool{t.startId: true}
+	level := []traversalVisit{{nodeId: t.startId}}
+	if err := stream.push(level[0]); err != nil {
+		return err
+	}
+
+	for depth := 0; len(level) > 0 && (t.maxDepth <= 0 || depth < t.maxDepth); depth++ {
+		var next []traversalVisit
+		for _, current := range level {
+			for _, edge := range edges[current.nodeId] {
+				if stream.done() {
+					return nil
+				}
+				to := otherEndpoint(edge, current.nodeId)
+				if visited[to] {
+					continue
+				}
+				visited[to] = true
+				visit := traversalVisit{nodeId: to, edge: edge, depth: depth + 1}
+				next = append(next, visit)
+				if err := stream.push(visit); err != nil {
+					return err
+				}
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// depthFirst follows every path as deep as possible before it backtracks.
+// Every node is visited once, through the first edge that reaches it. With a
+// max depth, a node reached again on a shorter path is expanded again, so the
+// nodes behind it are not cut off by the longer path that visited it first.
+func (t *Traversal) depthFirst(stream *traversalStream, edges map[string][]*EdgeCore) error {
+	visited := map[string]bool{t.startId: true}
+	expanded := make(map[string]int)
+
+	var walk func(current traversalVisit) error
+	walk = func(current traversalVisit) error {
+		if stream.done() || (t.maxDepth > 0 && current.depth >= t.maxDepth) {
+			return nil
+		}
+		if depth, ok := expanded[current.nodeId]; ok && (t.maxDepth <= 0 || depth <= current.depth) {
+			return nil
+		}
+		expanded[current.nodeId] = current.depth
+
+		for _, edge := range edges[current.nodeId] {
+			if stream.done() {
+				return nil
+			}
+			next := traversalVisit{nodeId: otherEndpoint(edge, current.nodeId), edge: edge, depth: current.depth + 1}
+			if !visited[next.nodeId] {
+				visited[next.nodeId] = true
+				if err := stream.push(next); err != nil {
+					return err
+				}
+			}
+			if err := walk(next); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := traversalVisit{nodeId: t.startId}
+	if err := stream.push(start); err != nil {
+		return err
+	}
+	return walk(start)
+}
+
+// traversalStream hands visits to the callback of Each. Visits are loaded in
+// batches, and the stream is done once the callback returned false or the
+// limit of the traversal is reached.
+type traversalStream struct {
+	traversal *Traversal
+	fn        func(step *TraversalStep) bool
+	pending   []traversalVisit
+	visited   int
+	stopped   bool
+}
+
+func (s *traversalStream) push(visit traversalVisit) error {
+	s.pending = append(s.pending, visit)
+	s.visited++
+	if len(s.pending) < traversalBatchSize {
+		return nil
+	}
+	return s.flush()
+}
+
+func (s *traversalStream) done() bool {
+	return s.stopped || (s.traversal.limit > 0 && s.visited >= s.traversal.limit)
+}
+
+func (s *traversalStream) flush() error {
+	if s.stopped || len(s.pending) == 0 {
+		return nil
+	}
+	steps, err := s.traversal.load(s.pending)
+	if err != nil {
+		return err
+	}
+	s.pending = nil
+	for _, step := range steps {
+		if !s.fn(step) {
+			s.stopped = true
+			return nil
+		}
+	}
+	return nil
+}
+
+// load turns a batch of visits into steps, loading nodes and edges in bulk.
+func (t *Traversal) load(visits []traversalVisit) ([]*TraversalStep, error) {
+	nodeIds := make([]string, 0, len(visits))
+	edgeCores := make([]*EdgeCore, 0, len(visits))
+	for _, visit := range visits {
+		nodeIds = append(nodeIds, visit.nodeId)
+		if visit.edge != nil {
+			edgeCores = append(edgeCores, visit.edge)
+		}
+	}
+
+	var cores []*NodeCore
+	if err := t.repository.db.Where("id IN ?", nodeIds).Find(&cores).Error; err != nil {
+		return nil, err
+	}
+	nodes, err := t.repository.loadNodes(cores, t.fetch)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := t.repository.loadEdges(edgeCores, t.fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	nodesById := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		nodesById[node.Core.Id] = node
+	}
+	edgesById := make(map[string]*Edge, len(edges))
+	for _, edge := range edges {
+		edgesById[edge.Core.Id] = edge
+	}
+
+	steps := make([]*TraversalStep, 0, len(visits))
+	for _, visit := range visits {
+		step := &TraversalStep{Node: nodesById[visit.nodeId], Depth: visit.depth}
+		if visit.edge != nil {
+			step.Edge = edgesById[visit.edge.Id]
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
@@ -0,0 +1,194 @@
+package nod
+
+import "gorm.io/gorm"
+
+// hierarchyDepthLimit bounds recursive hierarchy lookups, so a parent cycle
+// stored by older versions cannot make them recurse forever.
+const hierarchyDepthLimit = 1024
+
+// descendantsCTE defines node_tree as every descendant of the node bound to
+// the first placeholder, down to the depth bound to the second placeholder.
+const descendantsCTE = `WITH RECURSIVE "node_tree" ("id", "depth") AS (` +
+	`SELECT "id", 1 FROM "node_cores" WHERE "parent_id" = ? ` +
+	`UNION ALL ` +
+	`SELECT "node_cores"."id", "node_tree"."depth" + 1 FROM "node_cores" ` +
+	`JOIN "node_tree" ON "node_cores"."parent_id" = "node_tree"."id" ` +
+	`WHERE "node_tree"."depth" < ?)`
+
+// ancestorsCTE defines node_tree as every ancestor of the node bound to the
+// first placeholder, up to the depth bound to the second placeholder.
+const ancestorsCTE = `WITH RECURSIVE "node_tree" ("id", "parent_id", "depth") AS (` +
+	`SELECT "parents"."id", "parents"."parent_id", 1 FROM "node_cores" ` +
+	`JOIN "node_cores" AS "parents" ON "parents"."id" = "node_cores"."parent_id" ` +
+	`WHERE "node_cores"."id" = ? ` +
+	`UNION ALL ` +
+	`SELECT "node_cores"."id", "node_cores"."parent_id", "node_tree"."depth" + 1 FROM "node_cores" ` +
+	`JOIN "node_tree" ON "node_cores"."id" = "node_tree"."parent_id" ` +
+	`WHERE "node_tree"."depth" < ?)`
+
+const selectNodeTree = ` SELECT "node_cores".*, "node_tree"."depth" FROM "node_cores" ` +
+	`JOIN "node_tree" ON "node_tree"."id" = "node_cores"."id" ` +
+	`ORDER BY "node_tree"."depth", "node_cores"."rank", "node_cores"."name", "node_cores"."id"`
+
+type treeCore struct {
+	NodeCore
+	Depth int
+}
+
+// Children returns the direct children of the node with the given id in sibling
+// rank order.
+func (scope *NodeScope[T]) Children(id string) ([]*TreeNode[T], error) {
+	var cores []*treeCore
+	err := scope.repository.db.Model(&NodeCore{}).
+		Select("*, 1 AS depth").
+		Where("parent_id = ?", id).
+		Order("rank").
+		Order("name").
+		Order("id").
+		Scan(&cores).Error
+	if err != nil {
+		return nil, err
+	}
+	return treeNodes[T](scope.repository, cores)
+}
+
+// Descendants returns every node below the node with the given id, ordered by
+// depth. A maxDepth of zero or less walks the whole subtree.
+func (scope *NodeScope[T]) Descendants(id string, maxDepth int) ([]*TreeNode[T], error) {
+	db := scope.repository.db
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return nil, err
+	}
+
+	var cores []*treeCore
+	if features.closure {
+		err = db.Raw(selectClosureDescendants, id, depthBound(maxDepth)).Scan(&cores).Error
+	} else {
+		err = db.Raw(descendantsCTE+selectNodeTree, id, depthBound(maxDepth)).Scan(&cores).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return treeNodes[T](scope.repository, cores)
+}
+
+// Ancestors returns the chain of parents of the node with the given id,
+// starting with its direct parent and ending with the root.
+func (scope *NodeScope[T]) Ancestors(id string) ([]*TreeNode[T], error) {
+	db := scope.repository.db
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return nil, err
+	}
+
+	var cores []*treeCore
+	if features.closure {
+		err = db.Raw(selectClosureAncestors, id).Scan(&cores).Error
+	} else {
+		err = db.Raw(ancestorsCTE+selectNodeTree, id, hierarchyDepthLimit).Scan(&cores).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return treeNodes[T](scope.repository, cores)
+}
+
+// Move places the node with the given id under newParentId, or makes it a root
+// node when newParentId is nil. Only the parent reference and the sibling rank
+// are rewritten; KV, content and tags are left untouched. A node moved to a new
+// parent is placed after its last child. Moving a node below itself or below
+// one of its descendants fails with ParentCycleError.
+func (scope *NodeScope[T]) Move(id string, newParentId *string) error {
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return err
+	}
+
+	return scope.repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkParentCycle(tx, features, id, newParentId); err != nil {
+			return err
+		}
+
+		var core NodeCore
+		if err := tx.First(&core, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		moved := core
+		moved.ParentId = newParentId
+		if !sameSiblingGroup(&core, &moved) || !isValidRank(core.Rank) {
+			last, err := lastSiblingRank(tx, newParentId, core.NamespaceId, id)
+			if err != nil {
+				return err
+			}
+			moved.Rank = rankBetween(last, "")
+		}
+
+		if err := ensureUniqueSiblingName(tx, features, &moved); err != nil {
+			return err
+		}
+
+		return updateNodePlacement(tx, features, id, newParentId, moved.Rank)
+	})
+}
+
+// checkParentCycle fails when parentId is nodeId itself or one of its
+// descendants, i.e. when nodeId would appear among its own ancestors.
+func checkParentCycle(tx *gorm.DB, features schemaFeatures, nodeId string, parentId *string) error {
+	if parentId == nil {
+		return nil
+	}
+	if *parentId == nodeId {
+		return NewParentCycleError(nodeId, *parentId)
+	}
+
+	var count int64
+	var err error
+	if features.closure {
+		err = tx.Model(&NodeClosure{}).Where("ancestor_id = ? AND descendant_id = ?", nodeId, *parentId).Count(&count).Error
+	} else {
+		err = tx.Raw(ancestorsCTE+` SELECT COUNT(*) FROM "node_tree" WHERE "id" = ?`, *parentId, hierarchyDepthLimit, nodeId).
+			Scan(&count).Error
+	}
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return NewParentCycleError(nodeId, *parentId)
+	}
+	return nil
+}
+
+func depthBound(maxDepth int) int {
+	if maxDepth <= 0 || maxDepth > hierarchyDepthLimit {
+		return hierarchyDepthLimit
+	}
+	return maxDepth
+}
+
+func treeNodes[T any](repository *Repository, rows []*treeCore) ([]*TreeNode[T], error) {
+	cores := make([]*NodeCore, 0, len(rows))
+	for _, row := range rows {
+		cores = append(cores, &row.NodeCore)
+	}
+
+	nodes, err := repository.loadNodes(cores, fetchOptions{kv: true, content: true, tags: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*TreeNode[T], 0, len(nodes))
+	for i, node := range nodes {
+		model, err := modelFromNode[T](repository.adapters, node)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &TreeNode[T]{
+			Id:    node.Core.Id,
+			Depth: rows[i].Depth,
+			Model: model,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package nod
+
+import (
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const pathSeparator = "/"
+
+// ResolvePath returns the node addressed by a slash separated path of node
+// names, such as "/projects/nod/tasks", starting at the root nodes of the
+// given namespace. Every node on the path must belong to that namespace. It
+// returns gorm.ErrRecordNotFound when no node matches and
+// MultipleNodesFoundError when siblings share a name on the path. Every segment
+// is looked up through the index on parent and name.
+func (scope *NodeScope[T]) ResolvePath(namespaceId *string, path string) (*T, error) {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil, NewInvalidPathError(path)
+	}
+
+	last := "path_" + strconv.Itoa(len(segments)-1)
+	db := scope.repository.db.Table("node_cores AS path_0").
+		Select(last+".id").
+		Where("path_0.parent_id IS NULL AND path_0.name = ?", segments[0]).
+		Scopes(pathNamespace("path_0", namespaceId))
+	for i, segment := range segments[1:] {
+		alias := "path_" + strconv.Itoa(i+1)
+		parent := "path_" + strconv.Itoa(i)
+		db = db.Joins("JOIN node_cores AS "+alias+" ON "+alias+".parent_id = "+parent+".id AND "+alias+".name = ?", segment).
+			Scopes(pathNamespace(alias, namespaceId))
+	}
+
+	var ids []string
+	if err := db.Limit(2).Pluck(last+".id", &ids).Error; err != nil {
+		return nil, err
+	}
+	switch len(ids) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return scope.GetNode(ids[0])
+	default:
+		return nil, NewMultipleNodesFoundError()
+	}
+}
+
+// PathOf returns the slash separated path of node names that leads from the
+// root to the node with the given id. ResolvePath finds the node again by
+// that path when unique sibling names are enabled, which keeps the separator
+// out of node names. The ancestors are read from the closure table when it is
+// installed.
+func (scope *NodeScope[T]) PathOf(id string) (string, error) {
+	db := scope.repository.db
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return "", err
+	}
+
+	var node NodeCore
+	if err := db.Select("name").First(&node, "id = ?", id).Error; err != nil {
+		return "", err
+	}
+
+	var names []string
+	if features.closure {
+		err = db.Raw(selectClosureAncestorNames, id).Scan(&names).Error
+	} else {
+		err = db.Raw(ancestorsCTE+` SELECT "node_cores"."name" FROM "node_cores" `+
+			`JOIN "node_tree" ON "node_tree"."id" = "node_cores"."id" ORDER BY "node_tree"."depth" DESC`, id, hierarchyDepthLimit).
+			Scan(&names).Error
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return pathSeparator + strings.Join(append(names, node.Name), pathSeparator), nil
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, pathSeparator) {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func pathNamespace(alias string, namespaceId *string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if namespaceId == nil {
+			return db.Where(alias + ".namespace_id IS NULL")
+		}
+		return db.Where(alias+".namespace_id = ?", *namespaceId)
+	}
+}
+
+// ensureUniqueSiblingName fails with DuplicateNodeNameError when unique sibling
+// names are enabled and another node in the same namespace under the same
+// parent already uses the name of core. Names containing the path separator
+// fail with InvalidNodeNameError, as PathOf could not be resolved back.
+func ensureUniqueSiblingName(tx *gorm.DB, features schemaFeatures, core *NodeCore) error {
+	if !features.uniqueSiblingNames {
+		return nil
+	}
+	if strings.Contains(core.Name, pathSeparator) {
+		return NewInvalidNodeNameError(core.Name)
+	}
+
+	db := tx.Model(&NodeCore{}).Where("name = ? AND id <> ?", core.Name, core.Id)
+	if core.NamespaceId == nil {
+		db = db.Where("namespace_id IS NULL")
+	} else {
+		db = db.Where("namespace_id = ?", *core.NamespaceId)
+	}
+	if core.ParentId == nil {
+		db = db.Where("parent_id IS NULL")
+	} else {
+		db = db.Where("parent_id = ?", *core.ParentId)
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return NewDuplicateNodeNameError(core.ParentId, core.Name)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package nod
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// InsertBefore places the node with the given id directly before siblingId,
+// under the sibling's parent. Both nodes must be in the same namespace, or the
+// call fails with NamespaceMismatchError.
+func (scope *NodeScope[T]) InsertBefore(id string, siblingId string) error {
+	return scope.placeNextTo(id, siblingId, 0)
+}
+
+// InsertAfter places the node with the given id directly after siblingId,
+// under the sibling's parent. Both nodes must be in the same namespace, or the
+// call fails with NamespaceMismatchError.
+func (scope *NodeScope[T]) InsertAfter(id string, siblingId string) error {
+	return scope.placeNextTo(id, siblingId, 1)
+}
+
+// MoveToIndex moves the node with the given id to the given position among its
+// siblings. Indexes past the last sibling place the node at the end.
+func (scope *NodeScope[T]) MoveToIndex(id string, index int) error {
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return err
+	}
+
+	return scope.repository.db.Transaction(func(tx *gorm.DB) error {
+		var core NodeCore
+		if err := tx.First(&core, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		siblings, err := orderedSiblings(tx, core.ParentId, core.NamespaceId, id)
+		if err != nil {
+			return err
+		}
+		index = max(0, min(index, len(siblings)))
+
+		return updateNodePlacement(tx, features, id, core.ParentId, rankAt(siblings, index))
+	})
+}
+
+// placeNextTo places the node right before the sibling when offset is 0 and
+// right after it when offset is 1. Only the moved node gets a new rank. A node
+// placed next to itself keeps its place.
+func (scope *NodeScope[T]) placeNextTo(id string, siblingId string, offset int) error {
+	features, err := scope.repository.enabledFeatures()
+	if err != nil {
+		return err
+	}
+
+	return scope.repository.db.Transaction(func(tx *gorm.DB) error {
+		if id == siblingId {
+			return tx.Select("id").First(&NodeCore{}, "id = ?", id).Error
+		}
+
+		var sibling NodeCore
+		if err := tx.First(&sibling, "id = ?", siblingId).Error; err != nil {
+			return err
+		}
+		var core NodeCore
+		if err := tx.First(&core, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if !sameNamespace(&core, &sibling) {
+			return NewNamespaceMismatchError(id, siblingId)
+		}
+		if err := checkParentCycle(tx, features, id, sibling.ParentId); err != nil {
+			return err
+		}
+
+		core.ParentId = sibling.ParentId
+		if err := ensureUniqueSiblingName(tx, features, &core); err != nil {
+			return err
+		}
+
+		siblings, err := orderedSiblings(tx, sibling.ParentId, sibling.NamespaceId, id)
+		if err != nil {
+			return err
+		}
+		index := len(siblings)
+		for i, candidate := range siblings {
+			if candidate.Id == siblingId {
+				index = i + offset
+				break
+			}
+		}
+
+		return updateNodePlacement(tx, features, id, sibling.ParentId, rankAt(siblings, index))
+	})
+}
+
+func updateNodePlacement(tx *gorm.DB, features schemaFeatures, id string, parentId *string, rank string) error {
+	result := tx.Model(&NodeCore{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"parent_id": parentId, "rank": rank})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return syncNodeClosure(tx, features, id, parentId)
+}
+
+// rankAt returns a rank that places a node at index within siblings.
+func rankAt(siblings []*NodeCore, index int) string {
+	lower, upper := "", ""
+	if index > 0 {
+		lower = siblings[index-1].Rank
+	}
+	if index < len(siblings) {
+		upper = siblings[index].Rank
+	}
+	return rankBetween(lower, upper)
+}
+
+// siblingGroup restricts a query to the children of parentId. Root nodes are
+// grouped by namespace.
+func siblingGroup(parentId *string, namespaceId *string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if parentId != nil {
+			return db.Where("parent_id = ?", *parentId)
+		}
+		if namespaceId != nil {
+			return db.Where("parent_id IS NULL AND namespace_id = ?", *namespaceId)
+		}
+		return db.Where("parent_id IS NULL AND namespace_id IS NULL")
+	}
+}
+
+// orderedSiblings returns the sibling group in rank order without the node
+// with excludeId. Siblings stored without ranks, or with tied ranks, are
+// renumbered first so that every gap between neighbours can take a new rank.
+func orderedSiblings(tx *gorm.DB, parentId *string, namespaceId *string, excludeId string) ([]*NodeCore, error) {
+	var siblings []*NodeCore
+	err := tx.Select("id", "rank").
+		Scopes(siblingGroup(parentId, namespaceId)).
+		Order("rank").
+		Order("name").
+		Order("id").
+		Find(&siblings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if !ranksAreOrdered(siblings) {
+		ranks := ranksBetween("", "", len(siblings))
+		for i, sibling := range siblings {
+			sibling.Rank = ranks[i]
+			if err := tx.Model(&NodeCore{}).Where("id = ?", sibling.Id).Update("rank", sibling.Rank).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := make([]*NodeCore, 0, len(siblings))
+	for _, sibling := range siblings {
+		if sibling.Id != excludeId {
+			result = append(result, sibling)
+		}
+	}
+	return result, nil
+}
+
+func ranksAreOrdered(siblings []*NodeCore) bool {
+	for i, sibling := range siblings {
+		if !isValidRank(sibling.Rank) {
+			return false
+		}
+		if i > 0 && siblings[i-1].Rank >= sibling.Rank {
+			return false
+		}
+	}
+	return true
+}
+
+// lastSiblingRank returns the highest rank in the sibling group, ignoring the
+// node with excludeId.
+func lastSiblingRank(tx *gorm.DB, parentId *string, namespaceId *string, excludeId string) (string, error) {
+	var last *string
+	err := tx.Model(&NodeCore{}).
+		Select("MAX(rank)").
+		Scopes(siblingGroup(parentId, namespaceId)).
+		Where("id <> ? AND rank <> ''", excludeId).
+		Scan(&last).Error
+	if err != nil || last == nil {
+		return "", err
+	}
+	return *last, nil
+}
+
+// assignNodeRank fills in the rank of a node saved without one. A node that
+// keeps its parent keeps its stored rank; any other node is appended after its
+// last sibling.
+func assignNodeRank(tx *gorm.DB, core *NodeCore) error {
+	if core.Rank != "" {
+		if !isValidRank(core.Rank) {
+			return NewInvalidRankError(core.Rank)
+		}
+		return nil
+	}
+
+	var stored NodeCore
+	err := tx.Select("parent_id", "namespace_id", "rank").Take(&stored, "id = ?", core.Id).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && stored.Rank != "" && sameSiblingGroup(&stored, core) {
+		core.Rank = stored.Rank
+		return nil
+	}
+
+	last, err := lastSiblingRank(tx, core.ParentId, core.NamespaceId, core.Id)
+	if err != nil {
+		return err
+	}
+	core.Rank = rankBetween(last, "")
+	return nil
+}
+
+func sameSiblingGroup(a, b *NodeCore) bool {
+	if a.ParentId != nil || b.ParentId != nil {
+		return a.ParentId != nil && b.ParentId != nil && *a.ParentId == *b.ParentId
+	}
+	return sameNamespace(a, b)
+}
+
+func sameNamespace(a, b *NodeCore) bool {
+	if a.NamespaceId == nil || b.NamespaceId == nil {
+		return a.NamespaceId == nil && b.NamespaceId == nil
+	}
+	return *a.NamespaceId == *b.NamespaceId
+}
@@ -14,9 +14,9 @@ import (
 const sharedMemoryDSN = "file::memory:?mode=memory&cache=shared"
 
 // NewRepository creates a new nod Repository backed by SQLite at the given path.
-// Use ":memory:" for an in-memory database.
-func NewRepository(path string, log *slog.Logger, adapters *nod.AdapterRegistry) (*nod.Repository, error) {
-	db, err := initDB(log, path)
+// Use ":memory:" for an in-memory database. Options are passed to nod.Migrate.
+func NewRepository(path string, log *slog.Logger, adapters *nod.AdapterRegistry, options ...nod.MigrateOption) (*nod.Repository, error) {
+	db, err := initDB(log, path, options)
 	if err != nil {
 		return nil, err
 	}
@@ -28,11 +28,11 @@ func NewRepository(path string, log *slog.Logger, adapters *nod.AdapterRegistry)
 }
 
 // NewRepositoryInMemory creates a new nod Repository backed by an in-memory SQLite database.
-func NewRepositoryInMemory(log *slog.Logger, adapters *nod.AdapterRegistry) (*nod.Repository, error) {
-	return NewRepository(sharedMemoryDSN, log, adapters)
+func NewRepositoryInMemory(log *slog.Logger, adapters *nod.AdapterRegistry, options ...nod.MigrateOption) (*nod.Repository, error) {
+	return NewRepository(sharedMemoryDSN, log, adapters, options...)
 }
 
-func initDB(log *slog.Logger, path string) (*gorm.DB, error) {
+func initDB(log *slog.Logger, path string, options []nod.MigrateOption) (*gorm.DB, error) {
 	log.Debug(">> open database", slog.String("path", path))
 	db, err := gorm.Open(sqlite.New(sqlite.Config{
 		DSN:        path,
@@ -56,7 +56,7 @@ func initDB(log *slog.Logger, path string) (*gorm.DB, error) {
 	log.Debug("<< foreign keys enabled")
 
 	log.Debug(">> migrate database")
-	if err := nod.Migrate(db); err != nil {
+	if err := nod.Migrate(db, options...); err != nil {
 		return nil, err
 	}
 
@@ -40,3 +40,17 @@ func TestInit_ConfiguresSingleConnectionForInMemory(t *testing.T) {
 	stats := sqlDB.Stats()
 	require.Equal(t, 1, stats.MaxOpenConnections)
 }
+
+func TestPathLookupsUseParentNameIndex(t *testing.T) {
+	repo, err := NewRepository(":memory:", slog.Default(), nod.NewAdapterRegistry())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, repo.Close()) }()
+
+	for _, where := range []string{"parent_id = 'parent' AND name = 'name'", "parent_id IS NULL AND name = 'name'"} {
+		var plan []struct{ Detail string }
+		err := repo.DB().Raw("EXPLAIN QUERY PLAN SELECT id FROM node_cores WHERE " + where).Scan(&plan).Error
+		require.NoError(t, err)
+		require.NotEmpty(t, plan)
+		require.Contains(t, plan[0].Detail, "idx_parent_name")
+	}
+}
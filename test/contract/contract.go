@@ -13,9 +13,11 @@ func RunRepositoryContractTests(t *testing.T, factory RepositoryFactory) {
 
 	t.Run("NodeCrud", func(t *testing.T) { testNodeCrud(t, factory) })
 	t.Run("NodeTyped", func(t *testing.T) { testNodeTyped(t, factory) })
+	t.Run("NodeTree", func(t *testing.T) { testNodeTree(t, factory) })
 	t.Run("EdgeCrud", func(t *testing.T) { testEdgeCrud(t, factory) })
 	t.Run("EdgeTyped", func(t *testing.T) { testEdgeTyped(t, factory) })
 	t.Run("Query", func(t *testing.T) { testQueries(t, factory) })
 	t.Run("EdgeQuery", func(t *testing.T) { testEdgeQueries(t, factory) })
+	t.Run("Graph", func(t *testing.T) { testGraph(t, factory) })
 	t.Run("Transaction", func(t *testing.T) { testRepositoryTransaction(t, factory) })
 }
@@ -0,0 +1,158 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testEdgeSchema(t *testing.T, factory RepositoryFactory) {
+	repo := factory(t)
+	defer repo.Close()
+
+	nodes := repo.Nodes()
+	save := func(t *testing.T, id, kind string) string {
+		t.Helper()
+		_, err := nodes.SaveNode(&nod.Node{Core: nod.NodeCore{Id: id, Name: id, Kind: kind}})
+		require.NoError(t, err)
+		return id
+	}
+	alice := save(t, "alice", "person")
+	bob := save(t, "bob", "person")
+	teamA := save(t, "team-a", "team")
+	teamB := save(t, "team-b", "team")
+	doc := save(t, "doc", "document")
+
+	require.NoError(t, repo.EdgeSchemas().Register(nod.EdgeSchema{
+		Kind:        "member_of",
+		SourceKinds: []string{"person"},
+		TargetKinds: []string{"team"},
+		Unique:      true,
+	}))
+	require.NoError(t, repo.EdgeSchemas().Register(nod.EdgeSchema{
+		Kind:        "leads",
+		MaxOutgoing: 1,
+		MaxIncoming: 1,
+	}))
+
+	link := func(sourceId, targetId, kind string) (string, error) {
+		return repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: sourceId, TargetId: targetId, Kind: kind}})
+	}
+
+	t.Run("endpoint kinds", func(t *testing.T) {
+		_, err := link(alice, teamA, "member_of")
+		require.NoError(t, err)
+
+		_, err = link(doc, teamA, "member_of")
+		var kindErr *nod.EdgeEndpointKindError
+		require.ErrorAs(t, err, &kindErr)
+		require.Equal(t, "source", kindErr.Endpoint)
+		require.Equal(t, doc, kindErr.NodeId)
+		require.Equal(t, "document", kindErr.NodeKind)
+		require.Equal(t, []string{"person"}, kindErr.AllowedKinds)
+
+		_, err = link(alice, bob, "member_of")
+		require.ErrorAs(t, err, &kindErr)
+		require.Equal(t, "target", kindErr.Endpoint)
+		require.Equal(t, "person", kindErr.NodeKind)
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		existingId, err := link(bob, teamA, "member_of")
+		require.NoError(t, err)
+
+		_, err = link(bob, teamA, "member_of")
+		var duplicateErr *nod.DuplicateEdgeError
+		require.ErrorAs(t, err, &duplicateErr)
+		require.Equal(t, existingId, duplicateErr.ExistingId)
+
+		_, err = link(bob, teamB, "member_of")
+		require.NoError(t, err)
+
+		edge, err := repo.Edges().GetEdge(existingId)
+		require.NoError(t, err)
+		edge.Core.Status = "active"
+		_, err = repo.Edges().SaveEdge(edge)
+		require.NoError(t, err)
+	})
+
+	t.Run("unique pairs are backed by an index", func(t *testing.T) {
+		existingId, err := link(alice, teamB, "member_of")
+		require.NoError(t, err)
+		existing, err := repo.Edges().GetEdge(existingId)
+		require.NoError(t, err)
+		require.NotNil(t, existing.Core.UniquePair)
+
+		// An edge written behind the schema check, as by a concurrent save.
+		raced := existing.Core
+		raced.Id = "raced-member-of"
+		require.Error(t, repo.DB().Create(&raced).Error)
+
+		// A pair taken behind the check reaches the index and still fails with
+		// a typed error.
+		require.NoError(t, repo.Edges().DeleteEdge(existing))
+		raced.Kind = "member_of_raced"
+		require.NoError(t, repo.DB().Create(&raced).Error)
+		_, err = link(alice, teamB, "member_of")
+		var duplicateErr *nod.DuplicateEdgeError
+		require.ErrorAs(t, err, &duplicateErr)
+		require.Equal(t, raced.Id, duplicateErr.ExistingId)
+		require.NoError(t, repo.Edges().DeleteEdge(&nod.Edge{Core: raced}))
+
+		mentionId, err := link(alice, teamB, "mentions")
+		require.NoError(t, err)
+		mention, err := repo.Edges().GetEdge(mentionId)
+		require.NoError(t, err)
+		require.Nil(t, mention.Core.UniquePair)
+	})
+
+	t.Run("cardinality", func(t *testing.T) {
+		_, err := link(alice, teamB, "leads")
+		require.NoError(t, err)
+
+		_, err = link(alice, teamA, "leads")
+		var cardinalityErr *nod.EdgeCardinalityError
+		require.ErrorAs(t, err, &cardinalityErr)
+		require.Equal(t, alice, cardinalityErr.NodeId)
+		require.Equal(t, nod.DirectionOutgoing, cardinalityErr.Direction)
+		require.Equal(t, 1, cardinalityErr.Max)
+
+		_, err = link(bob, teamB, "leads")
+		require.ErrorAs(t, err, &cardinalityErr)
+		require.Equal(t, teamB, cardinalityErr.NodeId)
+		require.Equal(t, nod.DirectionIncoming, cardinalityErr.Direction)
+
+		_, err = link(bob, teamA, "leads")
+		require.NoError(t, err)
+	})
+
+	t.Run("rejected edges are not saved", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.SourceId.Equals(doc)).FindAll()
+		require.NoError(t, err)
+		require.Empty(t, edges)
+	})
+
+	t.Run("kinds without schema are not checked", func(t *testing.T) {
+		_, err := link(doc, doc, "mentions")
+		require.NoError(t, err)
+		_, err = link(doc, doc, "mentions")
+		require.NoError(t, err)
+	})
+
+	t.Run("transactions share the registry", func(t *testing.T) {
+		err := repo.Transaction(func(tx *nod.Repository) error {
+			_, err := tx.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: alice, TargetId: teamA, Kind: "member_of"}})
+			return err
+		})
+		var duplicateErr *nod.DuplicateEdgeError
+		require.ErrorAs(t, err, &duplicateErr)
+	})
+
+	t.Run("invalid schemas", func(t *testing.T) {
+		var emptyErr *nod.EdgeSchemaKindIsEmptyError
+		require.ErrorAs(t, repo.EdgeSchemas().Register(nod.EdgeSchema{}), &emptyErr)
+		var cardinalityErr *nod.InvalidEdgeSchemaCardinalityError
+		require.ErrorAs(t, repo.EdgeSchemas().Register(nod.EdgeSchema{Kind: "owns", MaxIncoming: -1}), &cardinalityErr)
+	})
+}
@@ -10,6 +10,10 @@ func testEdgeQueries(t *testing.T, factory RepositoryFactory) {
 	t.Run("Tags", func(t *testing.T) { testEdgeQueryTags(t, factory) })
 	t.Run("Content", func(t *testing.T) { testEdgeQueryContent(t, factory) })
 	t.Run("KV", func(t *testing.T) { testEdgeQueryKV(t, factory) })
+	t.Run("Endpoints", func(t *testing.T) { testEdgeQueryEndpoints(t, factory) })
+	t.Run("Order", func(t *testing.T) { testEdgeQueryOrder(t, factory) })
+	t.Run("Aggregate", func(t *testing.T) { testEdgeQueryAggregate(t, factory) })
+	t.Run("Preload", func(t *testing.T) { testEdgeQueryPreload(t, factory) })
 	t.Run("MixedParameters", func(t *testing.T) { testEdgeQueryMixedParameters(t, factory) })
 	t.Run("LogicalOperators", func(t *testing.T) { testEdgeQueryLogicalOperators(t, factory) })
 	t.Run("MultipleWhere", func(t *testing.T) { testEdgeQueryMultipleWhere(t, factory) })
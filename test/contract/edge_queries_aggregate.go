@@ -0,0 +1,64 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testEdgeQueryAggregate(t *testing.T, factory RepositoryFactory) {
+	repo := createEdgeQueryTestRepository(t, factory)
+
+	t.Run("counts matching edges", func(t *testing.T) {
+		count, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Kind.Equals("dependency")).Count()
+
+		require.NoError(t, err)
+		require.EqualValues(t, 2, count)
+	})
+
+	t.Run("reports whether any edge matches", func(t *testing.T) {
+		exists, err := nod.NewTypedEdgeQuery[nod.Edge](repo).Where(nod.EdgeFields.Kind.Equals("ownership")).Exists()
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		exists, err = nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Kind.Equals("missing")).Exists()
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("groups by a kv value", func(t *testing.T) {
+		groups, err := nod.NewEdgeQuery(repo).GroupBy(nod.KvString("color"))
+
+		require.NoError(t, err)
+		require.Equal(t, []nod.GroupCount{
+			{Value: "blue", Count: 1},
+			{Value: "green", Count: 1},
+			{Value: "red", Count: 2},
+		}, groups)
+	})
+
+	t.Run("aggregates numeric kv values", func(t *testing.T) {
+		for i, name := range []string{"light", "heavy"} {
+			weight := 1.5 + float64(i)*10
+			_, err := repo.Edges().SaveEdge(&nod.Edge{
+				Core: nod.EdgeCore{SourceId: queryEdgeSourceAID, TargetId: queryEdgeTargetBID, Name: name, Kind: "weighted"},
+				KV:   map[string]*nod.EdgeKV{"weight": {Key: "weight", ValueNumber: &weight}},
+			})
+			require.NoError(t, err)
+		}
+
+		query := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Kind.Equals("weighted"))
+		sum, err := nod.Sum(query, nod.KvFloat("weight"))
+		require.NoError(t, err)
+		require.Equal(t, 13.0, sum)
+
+		avg, err := query.Avg(nod.KvFloat("weight"))
+		require.NoError(t, err)
+		require.Equal(t, 6.5, *avg)
+
+		minimum, err := nod.Min(nod.NewEdgeQuery(repo), nod.KvInt("missing"))
+		require.NoError(t, err)
+		require.Nil(t, minimum)
+	})
+}
@@ -36,4 +36,22 @@ func testEdgeQueryContent(t *testing.T, factory RepositoryFactory) {
 		require.NoError(t, err)
 		require.Empty(t, edges)
 	})
+
+	t.Run("matches content containing a substring", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.Content("summary").Contains("alpha")).
+			FindAll()
+
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "alpha", "beta")
+	})
+
+	t.Run("matches content ignoring case", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.Content("body").IgnoreCase().Prefix("GAMMA")).
+			FindAll()
+
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "gamma")
+	})
 }
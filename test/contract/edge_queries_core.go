@@ -2,6 +2,7 @@ package contract
 
 import (
 	"testing"
+	"time"
 
 	"github.com/m87/nod"
 	"github.com/stretchr/testify/require"
@@ -70,4 +71,24 @@ func testEdgeQueryCoreFields(t *testing.T, factory RepositoryFactory) {
 			requireQueryEdgeNames(t, edges, tt.expected...)
 		})
 	}
+
+	t.Run("timestamps", func(t *testing.T) {
+		created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		_, err := repo.Edges().SaveEdge(&nod.Edge{
+			Core: nod.EdgeCore{SourceId: queryEdgeSourceAID, TargetId: queryEdgeTargetAID, Name: "archived", Kind: "reference", CreatedAt: created},
+		})
+		require.NoError(t, err)
+
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.EdgeFields.CreatedAt.Between(created.Add(-time.Hour), created.Add(time.Hour))).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "archived")
+
+		edges, err = nod.NewEdgeQuery(repo).
+			Where(nod.EdgeFields.UpdatedAt.GreaterThan(created)).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "alpha", "archived", "beta", "delta", "gamma")
+	})
 }
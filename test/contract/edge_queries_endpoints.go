@@ -0,0 +1,80 @@
+package contract
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testEdgeQueryEndpoints(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+	_, err := repo.Nodes().SaveNode(&nod.Node{
+		Core:    nod.NodeCore{Id: "graph-done", Name: "done", Kind: "task", Status: "done"},
+		Content: map[string]*nod.NodeContent{"body": {Key: "body", Value: nod.Ptr("finished work")}},
+		Tags:    []*nod.Tag{{Name: "archived"}},
+	})
+	require.NoError(t, err)
+	_, err = repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{Id: "graph-a-done", SourceId: graphAID, TargetId: "graph-done", Kind: "depends_on"}})
+	require.NoError(t, err)
+
+	find := func(t *testing.T, expr nod.Expression, expected ...string) {
+		t.Helper()
+		edges, err := nod.NewEdgeQuery(repo).Where(expr).FindAll()
+		require.NoError(t, err)
+
+		actual := make([]string, 0, len(edges))
+		for _, edge := range edges {
+			actual = append(actual, edge.Core.Id)
+		}
+		sort.Strings(actual)
+		sort.Strings(expected)
+		require.Equal(t, expected, actual)
+	}
+
+	t.Run("target matches", func(t *testing.T) {
+		find(t, nod.EdgeFields.Target.Matches(nod.NodeFields.Status.Equals("done")), "graph-a-done")
+		find(t, nod.EdgeFields.Target.Matches(nod.KvString("title").Equals("task d")), "graph-b-d", "graph-c-d")
+		find(t, nod.EdgeFields.Target.Matches(nod.Tags().Has("archived")), "graph-a-done")
+		find(t, nod.EdgeFields.Target.Matches(nod.Content("body").Equals("finished work")), "graph-a-done")
+	})
+
+	t.Run("source matches", func(t *testing.T) {
+		find(t, nod.EdgeFields.Source.Matches(nod.NodeFields.Id.In([]string{graphDID, graphFID})), "graph-d-a", "graph-d-e", "graph-f-a")
+		find(t, nod.EdgeFields.Source.Matches(nod.NodeFields.Edges.IncomingCount("", nil).Equals(0)), "graph-f-a")
+	})
+
+	t.Run("nil node expressions match any endpoint", func(t *testing.T) {
+		all, err := nod.NewEdgeQuery(repo).FindAll()
+		require.NoError(t, err)
+		ids := make([]string, 0, len(all))
+		for _, edge := range all {
+			ids = append(ids, edge.Core.Id)
+		}
+
+		find(t, nod.EdgeFields.Source.Matches(nil), ids...)
+		find(t, nod.And(nod.EdgeFields.Kind.Equals("blocks"), nod.EdgeFields.Target.Matches(nil)), "graph-d-e")
+
+		none, err := nod.NewEdgeQuery(repo).Where(nod.Not(nod.EdgeFields.Target.Matches(nil))).FindAll()
+		require.NoError(t, err)
+		require.Empty(t, none)
+	})
+
+	t.Run("combines with edge expressions", func(t *testing.T) {
+		find(t, nod.And(
+			nod.EdgeFields.Kind.Equals("depends_on"),
+			nod.EdgeFields.Source.Matches(nod.NodeFields.Name.Equals("d")),
+		), "graph-d-a")
+		find(t, nod.And(
+			nod.EdgeFields.Source.Matches(nod.NodeFields.Name.Equals("a")),
+			nod.EdgeFields.Target.Matches(nod.NodeFields.Name.Equals("b")),
+		), "graph-a-b")
+	})
+
+	t.Run("is not supported in node queries", func(t *testing.T) {
+		_, err := nod.NewNodeQuery(repo).Where(nod.EdgeFields.Source.Matches(nod.NodeFields.Name.Equals("a"))).FindAll()
+		var scopeErr *nod.UnsupportedScopeError
+		require.ErrorAs(t, err, &scopeErr)
+	})
+}
@@ -45,4 +45,45 @@ func testEdgeQueryKV(t *testing.T, factory RepositoryFactory) {
 		require.NoError(t, err)
 		requireQueryEdgeNames(t, edges, "delta")
 	})
+
+	t.Run("matches the presence of a kv key", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.KvString("accent").Exists()).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "beta")
+
+		edges, err = nod.NewEdgeQuery(repo).
+			Where(nod.Not(nod.KvString("accent").Exists())).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "alpha", "delta", "gamma")
+	})
+
+	t.Run("compares numeric and boolean values", func(t *testing.T) {
+		for i, name := range []string{"light", "heavy"} {
+			weight := 1.5 + float64(i)*10
+			required := i == 0
+			_, err := repo.Edges().SaveEdge(&nod.Edge{
+				Core: nod.EdgeCore{SourceId: queryEdgeSourceAID, TargetId: queryEdgeTargetBID, Name: name, Kind: "weighted"},
+				KV: map[string]*nod.EdgeKV{
+					"weight":   {Key: "weight", ValueNumber: &weight},
+					"required": {Key: "required", ValueBool: &required},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.KvFloat("weight").GreaterThan(5)).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "heavy")
+
+		edges, err = nod.NewEdgeQuery(repo).
+			Where(nod.KvBool("required").Equals(true)).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "light")
+	})
 }
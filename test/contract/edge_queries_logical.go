@@ -70,4 +70,16 @@ func testEdgeQueryLogicalOperators(t *testing.T, factory RepositoryFactory) {
 		require.NoError(t, err)
 		requireQueryEdgeNames(t, edges, "beta", "gamma")
 	})
+
+	t.Run("not", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.Not(nod.Or(
+				nod.EdgeFields.Kind.Equals("dependency"),
+				nod.Tags().Has("news"),
+			))).
+			FindAll()
+
+		require.NoError(t, err)
+		requireQueryEdgeNames(t, edges, "delta")
+	})
 }
@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testEdgeQueryOrder(t *testing.T, factory RepositoryFactory) {
+	repo := createEdgeQueryTestRepository(t, factory)
+
+	names := func(edges []*nod.Edge) []string {
+		result := make([]string, 0, len(edges))
+		for _, edge := range edges {
+			result = append(result, edge.Core.Name)
+		}
+		return result
+	}
+
+	t.Run("orders by a core field", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).OrderBy(nod.EdgeFields.Name, nod.Descending).FindAll()
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"gamma", "delta", "beta", "alpha"}, names(edges))
+	})
+
+	t.Run("orders by a kv value and applies limit and offset", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).
+			OrderBy(nod.KvString("color"), nod.Ascending).
+			Limit(2).
+			Offset(1).
+			FindAll()
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"delta", "alpha"}, names(edges))
+	})
+
+	t.Run("pages through matching edges with cursors", func(t *testing.T) {
+		var collected []string
+		cursor := ""
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 4)
+			query := nod.NewEdgeQuery(repo).
+				Where(nod.EdgeFields.Status.NotIn([]string{"archived"})).
+				OrderBy(nod.Content("summary"), nod.Descending).
+				Limit(1)
+			if cursor != "" {
+				query.After(cursor)
+			}
+			page, err := query.FindPage()
+			require.NoError(t, err)
+			collected = append(collected, names(page.Items)...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+
+		require.Equal(t, []string{"gamma", "alpha", "beta"}, collected)
+	})
+
+	t.Run("typed queries forward ordering and pages", func(t *testing.T) {
+		page, err := nod.NewTypedEdgeQuery[nod.Edge](repo).
+			OrderBy(nod.EdgeFields.Name, nod.Ascending).
+			Limit(3).
+			FindPage()
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"alpha", "beta", "delta"}, names(page.Items))
+		require.NotEmpty(t, page.NextCursor)
+	})
+}
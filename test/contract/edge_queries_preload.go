@@ -0,0 +1,61 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testEdgeQueryPreload(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+
+	t.Run("endpoints are not loaded by default", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Id.Equals("graph-a-b")).FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 1)
+		require.Nil(t, edges[0].Source)
+		require.Nil(t, edges[0].Target)
+	})
+
+	t.Run("source and target", func(t *testing.T) {
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.EdgeFields.Kind.Equals("depends_on")).
+			WithSource().
+			WithTarget(nod.LoadKV()).
+			FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 6)
+
+		for _, edge := range edges {
+			require.NotNil(t, edge.Source)
+			require.NotNil(t, edge.Target)
+			require.Equal(t, edge.Core.SourceId, edge.Source.Core.Id)
+			require.Equal(t, edge.Core.TargetId, edge.Target.Core.Id)
+			require.Nil(t, edge.Source.KV)
+			require.Equal(t, "task "+edge.Target.Core.Name, *edge.Target.KV["title"].ValueText)
+		}
+	})
+
+	t.Run("endpoint options", func(t *testing.T) {
+		_, err := repo.Nodes().SaveNode(&nod.Node{
+			Core:    nod.NodeCore{Id: "graph-note", Name: "note", Kind: "note"},
+			Content: map[string]*nod.NodeContent{"body": {Key: "body", Value: nod.Ptr("remember")}},
+			Tags:    []*nod.Tag{{Name: "pinned"}},
+		})
+		require.NoError(t, err)
+		_, err = repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{Id: "graph-e-note", SourceId: graphEID, TargetId: "graph-note", Kind: "noted_in"}})
+		require.NoError(t, err)
+
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.EdgeFields.Kind.Equals("noted_in")).
+			WithTarget(nod.LoadContent(), nod.LoadTags()).
+			FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 1)
+		require.Nil(t, edges[0].Source)
+		require.Equal(t, "remember", *edges[0].Target.Content["body"].Value)
+		require.Len(t, edges[0].Target.Tags, 1)
+		require.Equal(t, "pinned", edges[0].Target.Tags[0].Name)
+	})
+}
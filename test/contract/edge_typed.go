@@ -7,4 +7,5 @@ func testEdgeTyped(t *testing.T, factory RepositoryFactory) {
 
 	t.Run("CustomEdgeCodec", func(t *testing.T) { testCustomEdgeCodec(t, factory) })
 	t.Run("CustomEdgeAdapter", func(t *testing.T) { testCustomEdgeAdapter(t, factory) })
+	t.Run("Relation", func(t *testing.T) { testRelation(t, factory) })
 }
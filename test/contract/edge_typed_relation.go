@@ -0,0 +1,145 @@
+package contract
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+type relationPerson struct {
+	Id   string
+	Name string
+}
+
+func (p *relationPerson) ToNode() (*nod.Node, error) {
+	return &nod.Node{Core: nod.NodeCore{Id: p.Id, Name: p.Name, Kind: "person"}}, nil
+}
+
+func (p *relationPerson) FromNode(node *nod.Node) error {
+	p.Id = node.Core.Id
+	p.Name = node.Core.Name
+	return nil
+}
+
+func (p *relationPerson) IsApplicable(node *nod.Node) bool {
+	return node.Core.Kind == "person"
+}
+
+type relationTeam struct {
+	Id   string
+	Name string
+}
+
+type relationTeamAdapter struct{}
+
+func (a *relationTeamAdapter) ToNode(team *relationTeam) (*nod.Node, error) {
+	return &nod.Node{Core: nod.NodeCore{Id: team.Id, Name: team.Name, Kind: "team"}}, nil
+}
+
+func (a *relationTeamAdapter) FromNode(node *nod.Node) (*relationTeam, error) {
+	return &relationTeam{Id: node.Core.Id, Name: node.Core.Name}, nil
+}
+
+func (a *relationTeamAdapter) IsApplicable(node *nod.Node) bool {
+	return node.Core.Kind == "team"
+}
+
+func testRelation(t *testing.T, factory RepositoryFactory) {
+	repo := factory(t)
+	defer func() { require.NoError(t, repo.Close()) }()
+	require.NoError(t, nod.RegisterNodeAdapter(repo.Adapters(), &relationTeamAdapter{}))
+
+	alice := &relationPerson{Id: "alice", Name: "Alice"}
+	bob := &relationPerson{Id: "bob", Name: "Bob"}
+	for _, person := range []*relationPerson{alice, bob} {
+		_, err := nod.Nodes[relationPerson](repo).SaveNode(person)
+		require.NoError(t, err)
+	}
+	core := &relationTeam{Id: "core", Name: "Core"}
+	docs := &relationTeam{Id: "docs", Name: "Docs"}
+	for _, team := range []*relationTeam{core, docs} {
+		_, err := nod.Nodes[relationTeam](repo).SaveNode(team)
+		require.NoError(t, err)
+	}
+
+	memberOf := nod.Relation[relationPerson, relationTeam](repo, "member_of")
+
+	t.Run("link", func(t *testing.T) {
+		require.NoError(t, memberOf.Link(alice, core))
+		require.NoError(t, memberOf.Link(alice, docs))
+		require.NoError(t, memberOf.Link(bob, core))
+		require.NoError(t, memberOf.Link(alice, core))
+
+		edges, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Kind.Equals("member_of")).FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 3)
+	})
+
+	t.Run("targets and sources", func(t *testing.T) {
+		teams, err := memberOf.TargetsOf(alice)
+		require.NoError(t, err)
+		require.Equal(t, []*relationTeam{core, docs}, teams)
+
+		people, err := memberOf.SourcesOf(core)
+		require.NoError(t, err)
+		require.Equal(t, []*relationPerson{alice, bob}, people)
+
+		people, err = memberOf.SourcesOf(&relationTeam{Id: "missing"})
+		require.NoError(t, err)
+		require.Empty(t, people)
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		require.NoError(t, memberOf.Unlink(alice, core))
+		require.NoError(t, memberOf.Unlink(alice, core))
+
+		teams, err := memberOf.TargetsOf(alice)
+		require.NoError(t, err)
+		require.Equal(t, []*relationTeam{docs}, teams)
+
+		people, err := memberOf.SourcesOf(core)
+		require.NoError(t, err)
+		require.Equal(t, []*relationPerson{bob}, people)
+	})
+
+	t.Run("other kinds are separate", func(t *testing.T) {
+		leads := nod.Relation[relationPerson, relationTeam](repo, "leads")
+		require.NoError(t, leads.Link(bob, docs))
+
+		teams, err := leads.TargetsOf(bob)
+		require.NoError(t, err)
+		require.Equal(t, []*relationTeam{docs}, teams)
+		teams, err = memberOf.TargetsOf(bob)
+		require.NoError(t, err)
+		require.Equal(t, []*relationTeam{core}, teams)
+	})
+
+	t.Run("concurrent links create one edge", func(t *testing.T) {
+		watches := nod.Relation[relationPerson, relationTeam](repo, "watches")
+		var wg sync.WaitGroup
+		errs := make([]error, 8)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = watches.Link(alice, docs)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+
+		count, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Kind.Equals("watches")).Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("unsaved models", func(t *testing.T) {
+		err := memberOf.Link(&relationPerson{Name: "Carol"}, core)
+		var idErr *nod.ModelIdIsEmptyError
+		require.ErrorAs(t, err, &idErr)
+	})
+}
@@ -0,0 +1,13 @@
+package contract
+
+import "testing"
+
+func testGraph(t *testing.T, factory RepositoryFactory) {
+	t.Helper()
+
+	t.Run("Traversal", func(t *testing.T) { testGraphTraversal(t, factory) })
+	t.Run("ShortestPath", func(t *testing.T) { testGraphShortestPath(t, factory) })
+	t.Run("Analytics", func(t *testing.T) { testGraphAnalytics(t, factory) })
+	t.Run("Symmetric", func(t *testing.T) { testGraphSymmetric(t, factory) })
+	t.Run("Pattern", func(t *testing.T) { testGraphPattern(t, factory) })
+}
@@ -0,0 +1,76 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/m87/nod/graph"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraphAnalytics(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+
+	t.Run("loads nodes and edges in batches", func(t *testing.T) {
+		g, err := graph.Load(repo, graph.Options{BatchSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{graphAID, graphBID, graphCID, graphDID, graphEID, graphFID, graphGID}, g.Nodes())
+		require.Equal(t, []string{graphAID, graphEID}, g.Successors(graphDID))
+		require.Equal(t, []string{graphDID, graphFID}, g.Predecessors(graphAID))
+
+		g, err = graph.Load(repo, graph.Options{NamespaceId: nod.Ptr("other")})
+		require.NoError(t, err)
+		require.Empty(t, g.Nodes())
+	})
+
+	t.Run("topological sort", func(t *testing.T) {
+		_, err := graph.TopologicalSort(repo, graph.Options{})
+		var cycleErr *graph.CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		require.Equal(t, []string{graphAID, graphBID, graphDID}, cycleErr.Cycle)
+
+		order, err := graph.TopologicalSort(repo, graph.Options{EdgeKinds: []string{"blocks"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{graphAID, graphBID, graphCID, graphDID, graphEID, graphFID, graphGID}, order)
+
+		order, err = graph.TopologicalSort(repo, graph.Options{EdgeKinds: []string{"blocks"}, Reverse: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{graphAID, graphBID, graphCID, graphEID, graphDID, graphFID, graphGID}, order)
+	})
+
+	t.Run("connected components", func(t *testing.T) {
+		strong, err := graph.StronglyConnectedComponents(repo, graph.Options{})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{graphAID, graphBID, graphCID, graphDID}, {graphEID}, {graphFID}, {graphGID}}, strong)
+
+		weak, err := graph.WeaklyConnectedComponents(repo, graph.Options{})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{graphAID, graphBID, graphCID, graphDID, graphEID, graphFID}, {graphGID}}, weak)
+
+		weak, err = graph.WeaklyConnectedComponents(repo, graph.Options{EdgeKinds: []string{"blocks"}})
+		require.NoError(t, err)
+		require.Len(t, weak, 6)
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		cycles, err := graph.Cycles(repo, graph.Options{}, 0)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{graphAID, graphBID, graphDID}, {graphAID, graphCID, graphDID}}, cycles)
+
+		cycles, err = graph.Cycles(repo, graph.Options{NodeKinds: []string{"task"}}, 1)
+		require.NoError(t, err)
+		require.Len(t, cycles, 1)
+	})
+
+	t.Run("resolves typed models", func(t *testing.T) {
+		strong, err := graph.StronglyConnectedComponents(repo, graph.Options{})
+		require.NoError(t, err)
+
+		components, err := graph.ResolveAll[nod.Node](repo, strong)
+		require.NoError(t, err)
+		require.Len(t, components, 4)
+		require.Len(t, components[0], 4)
+		require.Equal(t, "task c", requireString(t, components[0][2].KV["title"].ValueText))
+		require.Equal(t, graphGID, components[3][0].Core.Id)
+	})
+}
@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	graphAID = "graph-a"
+	graphBID = "graph-b"
+	graphCID = "graph-c"
+	graphDID = "graph-d"
+	graphEID = "graph-e"
+	graphFID = "graph-f"
+	graphGID = "graph-g"
+)
+
+// createGraphTestRepository stores the graph
+//
+//	f -> a -> b -> d -> e
+//	     a -> c -> d -> a
+//
+// where d -> e is a "blocks" edge, every other edge is a "depends_on" edge
+// and g is not connected at all. Edges carry a numeric "weight" KV.
+func createGraphTestRepository(t *testing.T, factory RepositoryFactory) *nod.Repository {
+	t.Helper()
+
+	repo := factory(t)
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+
+	for _, id := range []string{graphAID, graphBID, graphCID, graphDID, graphEID, graphFID, graphGID} {
+		_, err := repo.Nodes().SaveNode(&nod.Node{
+			Core: nod.NodeCore{Id: id, Name: id[len("graph-"):], Kind: "task"},
+			KV:   map[string]*nod.NodeKV{"title": {Key: "title", ValueText: nod.Ptr("task " + id[len("graph-"):])}},
+		})
+		require.NoError(t, err)
+	}
+
+	for _, edge := range []struct {
+		source, target, kind string
+		weight               float64
+	}{
+		{graphAID, graphBID, "depends_on", 1},
+		{graphAID, graphCID, "depends_on", 5},
+		{graphBID, graphDID, "depends_on", 10},
+		{graphCID, graphDID, "depends_on", 1},
+		{graphDID, graphAID, "depends_on", 1},
+		{graphDID, graphEID, "blocks", 1},
+		{graphFID, graphAID, "depends_on", 1},
+	} {
+		weight := edge.weight
+		_, err := repo.Edges().SaveEdge(&nod.Edge{
+			Core: nod.EdgeCore{
+				Id:       graphEdgeID(edge.source, edge.target),
+				SourceId: edge.source,
+				TargetId: edge.target,
+				Kind:     edge.kind,
+			},
+			KV: map[string]*nod.EdgeKV{"weight": {Key: "weight", ValueNumber: &weight}},
+		})
+		require.NoError(t, err)
+	}
+
+	return repo
+}
+
+func graphEdgeID(sourceId, targetId string) string {
+	return sourceId + "-" + targetId[len("graph-"):]
+}
@@ -0,0 +1,169 @@
+package contract
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testGraphShortestPath(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+
+	t.Run("finds the path with the fewest edges", func(t *testing.T) {
+		path, err := repo.ShortestPath(graphAID, graphDID, nod.PathOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "d"}, pathNodeNames(path))
+		require.Equal(t, []string{graphEdgeID(graphAID, graphBID), graphEdgeID(graphBID, graphDID)}, pathEdgeIds(path))
+		require.Equal(t, 2.0, path.Cost)
+		require.Equal(t, "task b", requireString(t, path.Nodes[1].KV["title"].ValueText))
+	})
+
+	t.Run("finds the cheapest weighted path", func(t *testing.T) {
+		path, err := repo.ShortestPath(graphAID, graphDID, nod.PathOptions{WeightKey: "weight"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "c", "d"}, pathNodeNames(path))
+		require.Equal(t, 6.0, path.Cost)
+		require.Equal(t, 5.0, *path.Edges[0].KV["weight"].ValueNumber)
+
+		path, err = repo.ShortestPath(graphAID, graphEID, nod.PathOptions{WeightKey: "weight"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "c", "d", "e"}, pathNodeNames(path))
+		require.Equal(t, 7.0, path.Cost)
+	})
+
+	t.Run("treats edges without weight as weight 1", func(t *testing.T) {
+		path, err := repo.ShortestPath(graphAID, graphDID, nod.PathOptions{WeightKey: "cost"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "d"}, pathNodeNames(path))
+		require.Equal(t, 2.0, path.Cost)
+	})
+
+	t.Run("follows the selected direction", func(t *testing.T) {
+		_, err := repo.ShortestPath(graphEID, graphAID, nod.PathOptions{})
+		var noPathErr *nod.NoPathError
+		require.ErrorAs(t, err, &noPathErr)
+		require.Equal(t, graphEID, noPathErr.FromId)
+		require.Equal(t, graphAID, noPathErr.ToId)
+
+		path, err := repo.ShortestPath(graphEID, graphAID, nod.PathOptions{Direction: nod.DirectionIncoming})
+		require.NoError(t, err)
+		require.Equal(t, []string{"e", "d", "b", "a"}, pathNodeNames(path))
+
+		path, err = repo.ShortestPath(graphEID, graphFID, nod.PathOptions{Direction: nod.DirectionBoth})
+		require.NoError(t, err)
+		require.Equal(t, []string{"e", "d", "a", "f"}, pathNodeNames(path))
+	})
+
+	t.Run("filters edges", func(t *testing.T) {
+		_, err := repo.ShortestPath(graphAID, graphEID, nod.PathOptions{Kinds: []string{"depends_on"}})
+		var noPathErr *nod.NoPathError
+		require.ErrorAs(t, err, &noPathErr)
+
+		path, err := repo.ShortestPath(graphAID, graphDID, nod.PathOptions{
+			Where:     nod.EdgeFields.SourceId.NotIn([]string{graphCID}),
+			WeightKey: "weight",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "d"}, pathNodeNames(path))
+		require.Equal(t, 11.0, path.Cost)
+	})
+
+	t.Run("reports unconnected and missing nodes", func(t *testing.T) {
+		_, err := repo.ShortestPath(graphAID, graphGID, nod.PathOptions{Direction: nod.DirectionBoth})
+		var noPathErr *nod.NoPathError
+		require.ErrorAs(t, err, &noPathErr)
+
+		_, err = repo.ShortestPath(graphAID, "missing", nod.PathOptions{})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("path to the start node", func(t *testing.T) {
+		path, err := repo.ShortestPath(graphAID, graphAID, nod.PathOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a"}, pathNodeNames(path))
+		require.Empty(t, path.Edges)
+		require.Zero(t, path.Cost)
+	})
+
+	t.Run("rejects weights that are not numbers", func(t *testing.T) {
+		repo := createGraphTestRepository(t, factory)
+		edgeId, err := repo.Edges().SaveEdge(&nod.Edge{
+			Core: nod.EdgeCore{SourceId: graphAID, TargetId: graphEID, Kind: "depends_on"},
+			KV:   map[string]*nod.EdgeKV{"weight": {Key: "weight", ValueText: nod.Ptr("heavy")}},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.ShortestPath(graphAID, graphEID, nod.PathOptions{WeightKey: "weight"})
+		var weightErr *nod.InvalidEdgeWeightError
+		require.ErrorAs(t, err, &weightErr)
+		require.Equal(t, edgeId, weightErr.EdgeId)
+		require.Equal(t, "weight", weightErr.Key)
+	})
+
+	t.Run("reads the graph only up to the target", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		const size = 300
+		var cores []*nod.NodeCore
+		var edges []*nod.EdgeCore
+		for i := range size {
+			id := fmt.Sprintf("chain-%d", i)
+			cores = append(cores, &nod.NodeCore{Id: id, Name: id, Kind: "task"})
+			if i > 0 {
+				edges = append(edges, &nod.EdgeCore{Id: "chain-edge-" + id, SourceId: fmt.Sprintf("chain-%d", i-1), TargetId: id, Kind: "next"})
+			}
+		}
+		require.NoError(t, repo.DB().CreateInBatches(cores, 100).Error)
+		require.NoError(t, repo.DB().CreateInBatches(edges, 100).Error)
+
+		var edgeRows int64
+		err := repo.DB().Callback().Query().After("gorm:query").Register("contract:count_path_edge_rows", func(db *gorm.DB) {
+			if db.Statement.Table == "edge_cores" {
+				edgeRows += db.Statement.RowsAffected
+			}
+		})
+		require.NoError(t, err)
+
+		for _, options := range []nod.PathOptions{{}, {WeightKey: "weight"}} {
+			edgeRows = 0
+			path, err := repo.ShortestPath("chain-0", "chain-2", options)
+			require.NoError(t, err)
+			require.Equal(t, []string{"chain-0", "chain-1", "chain-2"}, pathNodeNames(path))
+			require.Equal(t, int64(2), edgeRows)
+		}
+	})
+
+	t.Run("rejects negative weights", func(t *testing.T) {
+		repo := createGraphTestRepository(t, factory)
+		weight := -1
+		_, err := repo.Edges().SaveEdge(&nod.Edge{
+			Core: nod.EdgeCore{SourceId: graphAID, TargetId: graphEID, Kind: "depends_on"},
+			KV:   map[string]*nod.EdgeKV{"weight": {Key: "weight", ValueInt: &weight}},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.ShortestPath(graphAID, graphEID, nod.PathOptions{WeightKey: "weight"})
+		var weightErr *nod.NegativeEdgeWeightError
+		require.ErrorAs(t, err, &weightErr)
+		require.Equal(t, -1.0, weightErr.Weight)
+	})
+}
+
+func pathNodeNames(path *nod.Path) []string {
+	names := make([]string, 0, len(path.Nodes))
+	for _, node := range path.Nodes {
+		names = append(names, node.Core.Name)
+	}
+	return names
+}
+
+func pathEdgeIds(path *nod.Path) []string {
+	ids := make([]string, 0, len(path.Edges))
+	for _, edge := range path.Edges {
+		ids = append(ids, edge.Core.Id)
+	}
+	return ids
+}
@@ -0,0 +1,137 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraphPattern(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+
+	d, err := repo.Nodes().GetNode(graphDID)
+	require.NoError(t, err)
+	d.Core.Status = "open"
+	_, err = repo.Nodes().SaveNode(d)
+	require.NoError(t, err)
+	level := 3
+	_, err = repo.Nodes().SaveNode(&nod.Node{
+		Core: nod.NodeCore{Id: "graph-person", Name: "person", Kind: "person"},
+		KV:   map[string]*nod.NodeKV{"level": {Key: "level", ValueInt: &level}},
+	})
+	require.NoError(t, err)
+	_, err = repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{Id: "graph-person-e", SourceId: "graph-person", TargetId: graphEID, Kind: "assigned_to"}})
+	require.NoError(t, err)
+
+	names := func(matches []*nod.PatternMatch, alias string) []string {
+		result := make([]string, 0, len(matches))
+		for _, match := range matches {
+			result = append(result, match.Nodes[alias].Core.Name)
+		}
+		return result
+	}
+
+	t.Run("builder", func(t *testing.T) {
+		matches, err := nod.NewPatternQuery(repo).
+			Node("a", nod.NodeFields.Kind.Equals("task")).
+			Edge("", nod.DirectionOutgoing, nod.EdgeFields.Kind.Equals("depends_on")).
+			Node("", nod.NodeFields.Name.Equals("d")).
+			FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"b", "c"}, names(matches, "a"))
+		require.Empty(t, matches[0].Edges)
+	})
+
+	t.Run("parsed pattern", func(t *testing.T) {
+		query, err := nod.ParsePattern(repo, "(a:task {status:'open'})-[r:blocks]->(b:task)<-[:assigned_to]-(p:person)")
+		require.NoError(t, err)
+		matches, err := query.FindAll()
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, "d", matches[0].Nodes["a"].Core.Name)
+		require.Equal(t, "e", matches[0].Nodes["b"].Core.Name)
+		require.Equal(t, "person", matches[0].Nodes["p"].Core.Name)
+		require.Equal(t, "graph-d-e", matches[0].Edges["r"].Core.Id)
+	})
+
+	t.Run("directions", func(t *testing.T) {
+		query, err := nod.ParsePattern(repo, `(x {name: "d"})-[]-(y)`)
+		require.NoError(t, err)
+		matches, err := query.FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "e"}, names(matches, "y"))
+
+		query, err = nod.ParsePattern(repo, `(x)<--(y {name: "d"})`)
+		require.NoError(t, err)
+		matches, err = query.FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "e"}, names(matches, "x"))
+	})
+
+	t.Run("repeated aliases close cycles", func(t *testing.T) {
+		query, err := nod.ParsePattern(repo, "(x)-[:depends_on]->(y)-->(z)-->(x)")
+		require.NoError(t, err)
+		matches, err := query.Where("x", nod.NodeFields.Name.Equals("a")).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"b", "c"}, names(matches, "y"))
+		require.Equal(t, []string{"d", "d"}, names(matches, "z"))
+	})
+
+	t.Run("KV properties", func(t *testing.T) {
+		query, err := nod.ParsePattern(repo, "(t {title: 'task e'})<--(p {level: 3})")
+		require.NoError(t, err)
+		matches, err := query.FindAll()
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, "e", matches[0].Nodes["t"].Core.Name)
+		require.Equal(t, "person", matches[0].Nodes["p"].Core.Name)
+	})
+
+	t.Run("edge constraints and loading", func(t *testing.T) {
+		matches, err := nod.NewPatternQuery(repo).
+			Node("from", nil).
+			Edge("w", nod.DirectionOutgoing, nil).
+			Node("to", nil).
+			Where("w", nod.EdgeFields.Kind.Equals("blocks")).
+			WithKV().
+			FindAll()
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, 1.0, *matches[0].Edges["w"].KV["weight"].ValueNumber)
+		require.Equal(t, "task e", *matches[0].Nodes["to"].KV["title"].ValueText)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		query, err := nod.ParsePattern(repo, "(a)-->(b)")
+		require.NoError(t, err)
+		matches, err := query.Limit(2).FindAll()
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+	})
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		var invalidErr *nod.InvalidPatternError
+		_, err := nod.NewPatternQuery(repo).Node("a", nil).Node("b", nil).FindAll()
+		require.ErrorAs(t, err, &invalidErr)
+		_, err = nod.NewPatternQuery(repo).FindAll()
+		require.ErrorAs(t, err, &invalidErr)
+		_, err = nod.NewPatternQuery(repo).Node("a", nil).Where("b", nil).FindAll()
+		require.ErrorAs(t, err, &invalidErr)
+		_, err = nod.NewPatternQuery(repo).Node("", nil).FindAll()
+		require.ErrorAs(t, err, &invalidErr)
+
+		var syntaxErr *nod.PatternSyntaxError
+		for pattern, offset := range map[string]int{
+			"(a":                 2,
+			"(a)->(b)":           4,
+			"(a)<-->(b)":         3,
+			"(a {name: 1})":      10,
+			"(a {status: 'open)": 12,
+		} {
+			_, err = nod.ParsePattern(repo, pattern)
+			require.ErrorAs(t, err, &syntaxErr, pattern)
+			require.Equal(t, offset, syntaxErr.Offset, pattern)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/m87/nod/graph"
+	"github.com/stretchr/testify/require"
+)
+
+// createSymmetricTestRepository stores the nodes x, y, z and w linked by
+// symmetric "related_to" edges x - y and z - y, and by a directed "follows"
+// edge x -> w.
+func createSymmetricTestRepository(t *testing.T, factory RepositoryFactory) *nod.Repository {
+	t.Helper()
+
+	repo := factory(t)
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+	require.NoError(t, repo.EdgeSchemas().Register(nod.EdgeSchema{Kind: "related_to", Symmetric: true}))
+
+	for _, name := range []string{"x", "y", "z", "w"} {
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Id: "sym-" + name, Name: name, Kind: "topic"}})
+		require.NoError(t, err)
+	}
+	for _, edge := range []struct{ source, target, kind string }{
+		{"x", "y", "related_to"},
+		{"z", "y", "related_to"},
+		{"x", "w", "follows"},
+	} {
+		_, err := repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{
+			Id:       "sym-" + edge.source + "-" + edge.target,
+			SourceId: "sym-" + edge.source,
+			TargetId: "sym-" + edge.target,
+			Kind:     edge.kind,
+		}})
+		require.NoError(t, err)
+	}
+	return repo
+}
+
+func testGraphSymmetric(t *testing.T, factory RepositoryFactory) {
+	t.Run("duplicates in either order are rejected", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+
+		for _, pair := range [][2]string{{"sym-y", "sym-x"}, {"sym-x", "sym-y"}} {
+			_, err := repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: pair[0], TargetId: pair[1], Kind: "related_to"}})
+			var duplicateErr *nod.DuplicateEdgeError
+			require.ErrorAs(t, err, &duplicateErr)
+			require.Equal(t, "sym-x-y", duplicateErr.ExistingId)
+		}
+
+		_, err := repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: "sym-w", TargetId: "sym-x", Kind: "follows"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("node expressions match either endpoint", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+		find := func(t *testing.T, expr nod.Expression, expected ...string) {
+			t.Helper()
+			nodes, err := nod.NewNodeQuery(repo).Where(expr).FindAll()
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, expected...)
+		}
+
+		find(t, nod.NodeFields.Edges.Outgoing("related_to", nil), "x", "y", "z")
+		find(t, nod.NodeFields.Edges.Incoming("related_to", nod.NodeFields.Name.Equals("y")), "x", "z")
+		find(t, nod.NodeFields.Edges.OutgoingCount("related_to", nil).Equals(2), "y")
+		find(t, nod.NodeFields.Edges.Incoming("", nil), "x", "y", "z", "w")
+		find(t, nod.NodeFields.Edges.Incoming("follows", nil), "w")
+	})
+
+	t.Run("edge endpoints match either end", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+
+		edges, err := nod.NewEdgeQuery(repo).
+			Where(nod.EdgeFields.Source.Matches(nod.NodeFields.Name.Equals("y"))).
+			FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 2)
+		require.ElementsMatch(t, []string{"sym-x-y", "sym-z-y"}, []string{edges[0].Core.Id, edges[1].Core.Id})
+
+		edges, err = nod.NewEdgeQuery(repo).
+			Where(nod.EdgeFields.Target.Matches(nod.NodeFields.Name.Equals("x"))).
+			FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 1)
+		require.Equal(t, "sym-x-y", edges[0].Core.Id)
+	})
+
+	t.Run("traversal and paths follow either direction", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+
+		steps, err := repo.Traverse("sym-z").FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"z", "y", "x", "w"}, traversalNames(steps))
+
+		steps, err = repo.Traverse("sym-w").Direction(nod.DirectionIncoming).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"w", "x", "y", "z"}, traversalNames(steps))
+
+		path, err := repo.ShortestPath("sym-y", "sym-x", nod.PathOptions{})
+		require.NoError(t, err)
+		require.Len(t, path.Edges, 1)
+		require.Equal(t, "sym-x-y", path.Edges[0].Core.Id)
+
+		_, err = repo.ShortestPath("sym-w", "sym-x", nod.PathOptions{})
+		var noPathErr *nod.NoPathError
+		require.ErrorAs(t, err, &noPathErr)
+	})
+
+	t.Run("preloads include both endpoints", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+
+		nodes, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Kind.Equals("topic")).
+			WithOutgoingEdges("").
+			FindAll()
+		require.NoError(t, err)
+
+		outgoing := map[string][]string{}
+		for _, node := range nodes {
+			outgoing[node.Core.Name] = []string{}
+			for _, edge := range node.Outgoing {
+				outgoing[node.Core.Name] = append(outgoing[node.Core.Name], edge.Core.Id)
+			}
+		}
+		require.Equal(t, map[string][]string{
+			"x": {"sym-x-y", "sym-x-w"},
+			"y": {"sym-x-y", "sym-z-y"},
+			"z": {"sym-z-y"},
+			"w": {},
+		}, outgoing)
+	})
+
+	t.Run("relations link either way", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+		related := nod.Relation[nod.Node, nod.Node](repo, "related_to")
+		x, err := repo.Nodes().GetNode("sym-x")
+		require.NoError(t, err)
+		y, err := repo.Nodes().GetNode("sym-y")
+		require.NoError(t, err)
+
+		require.NoError(t, related.Link(y, x))
+		sources, err := related.SourcesOf(y)
+		require.NoError(t, err)
+		require.Len(t, sources, 2)
+
+		require.NoError(t, related.Unlink(y, x))
+		targets, err := related.TargetsOf(x)
+		require.NoError(t, err)
+		require.Empty(t, targets)
+	})
+
+	t.Run("graph analytics see both directions", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+
+		components, err := graph.StronglyConnectedComponents(repo, graph.Options{})
+		require.NoError(t, err)
+		require.Contains(t, components, []string{"sym-x", "sym-y", "sym-z"})
+	})
+
+	t.Run("delete removes mirrored edges", func(t *testing.T) {
+		repo := createSymmetricTestRepository(t, factory)
+		require.NoError(t, repo.DB().Create(&nod.EdgeCore{Id: "sym-y-x", SourceId: "sym-y", TargetId: "sym-x", Kind: "related_to"}).Error)
+
+		require.NoError(t, repo.Edges().DeleteEdge(&nod.Edge{Core: nod.EdgeCore{Id: "sym-x-y"}}))
+
+		edges, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.Kind.Equals("related_to")).FindAll()
+		require.NoError(t, err)
+		require.Len(t, edges, 1)
+		require.Equal(t, "sym-z-y", edges[0].Core.Id)
+	})
+}
@@ -0,0 +1,187 @@
+package contract
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testGraphTraversal(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+
+	t.Run("walks outgoing edges breadth first", func(t *testing.T) {
+		steps, err := repo.Traverse(graphAID).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, traversalNames(steps))
+		require.Equal(t, []int{0, 1, 1, 2, 3}, traversalDepths(steps))
+
+		require.Nil(t, steps[0].Edge)
+		require.Equal(t, graphEdgeID(graphBID, graphDID), steps[3].Edge.Core.Id)
+		require.Equal(t, graphEdgeID(graphDID, graphEID), steps[4].Edge.Core.Id)
+	})
+
+	t.Run("walks depth first", func(t *testing.T) {
+		steps, err := repo.Traverse(graphAID).Order(nod.DepthFirst).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "d", "e", "c"}, traversalNames(steps))
+		require.Equal(t, []int{0, 1, 2, 3, 1}, traversalDepths(steps))
+	})
+
+	t.Run("walks incoming edges", func(t *testing.T) {
+		steps, err := repo.Traverse(graphAID).Direction(nod.DirectionIncoming).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "d", "f", "b", "c"}, traversalNames(steps))
+	})
+
+	t.Run("walks both directions", func(t *testing.T) {
+		steps, err := repo.Traverse(graphEID).Direction(nod.DirectionBoth).MaxDepth(2).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"e", "d", "b", "c", "a"}, traversalNames(steps))
+	})
+
+	t.Run("filters edges", func(t *testing.T) {
+		steps, err := repo.Traverse(graphAID).Kinds("depends_on").FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d"}, traversalNames(steps))
+
+		steps, err = repo.Traverse(graphAID).Where(nod.EdgeFields.TargetId.NotIn([]string{graphBID})).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "c", "d", "e"}, traversalNames(steps))
+		require.Equal(t, []int{0, 1, 2, 3}, traversalDepths(steps))
+	})
+
+	t.Run("stops at max depth and limit", func(t *testing.T) {
+		steps, err := repo.Traverse(graphAID).MaxDepth(1).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c"}, traversalNames(steps))
+
+		steps, err = repo.Traverse(graphAID).Limit(4).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d"}, traversalNames(steps))
+
+		steps, err = repo.Traverse(graphAID).Order(nod.DepthFirst).Limit(3).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "d"}, traversalNames(steps))
+	})
+
+	t.Run("streams steps until stopped", func(t *testing.T) {
+		var names []string
+		err := repo.Traverse(graphAID).Each(func(step *nod.TraversalStep) bool {
+			names = append(names, step.Node.Core.Name)
+			return len(names) < 2
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, names)
+	})
+
+	t.Run("loads relations", func(t *testing.T) {
+		steps, err := repo.Traverse(graphCID).WithKV().MaxDepth(1).FindAll()
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		require.Equal(t, "task d", requireString(t, steps[1].Node.KV["title"].ValueText))
+		require.Equal(t, 1.0, *steps[1].Edge.KV["weight"].ValueNumber)
+	})
+
+	t.Run("visits a node without edges", func(t *testing.T) {
+		steps, err := repo.Traverse(graphGID).Direction(nod.DirectionBoth).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"g"}, traversalNames(steps))
+	})
+
+	t.Run("depth first expands nodes again on shorter paths", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		for _, id := range []string{"dfs-a", "dfs-b", "dfs-c", "dfs-d"} {
+			_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Id: id, Name: id[len("dfs-"):], Kind: "task"}})
+			require.NoError(t, err)
+		}
+		for _, edge := range [][2]string{{"dfs-a", "dfs-b"}, {"dfs-b", "dfs-c"}, {"dfs-a", "dfs-c"}, {"dfs-c", "dfs-d"}} {
+			_, err := repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: edge[0], TargetId: edge[1], Kind: "depends_on"}})
+			require.NoError(t, err)
+		}
+
+		steps, err := repo.Traverse("dfs-a").Order(nod.DepthFirst).MaxDepth(2).FindAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d"}, traversalNames(steps))
+		require.Equal(t, []int{0, 1, 2, 2}, traversalDepths(steps))
+	})
+
+	t.Run("reads edges in one query up to the depth and limit", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		const size = 300
+		var cores []*nod.NodeCore
+		var edges []*nod.EdgeCore
+		for i := range size {
+			id := fmt.Sprintf("chain-%d", i)
+			cores = append(cores, &nod.NodeCore{Id: id, Name: id, Kind: "task"})
+			if i > 0 {
+				edges = append(edges, &nod.EdgeCore{Id: "chain-edge-" + id, SourceId: fmt.Sprintf("chain-%d", i-1), TargetId: id, Kind: "next"})
+			}
+		}
+		require.NoError(t, repo.DB().CreateInBatches(cores, 100).Error)
+		require.NoError(t, repo.DB().CreateInBatches(edges, 100).Error)
+
+		var edgeQueries, edgeRows, nodeRows int64
+		err := repo.DB().Callback().Query().After("gorm:query").Register("contract:count_traversal_rows", func(db *gorm.DB) {
+			switch db.Statement.Table {
+			case "edge_cores":
+				edgeQueries++
+				edgeRows += db.Statement.RowsAffected
+			case "node_cores":
+				nodeRows += db.Statement.RowsAffected
+			}
+		})
+		require.NoError(t, err)
+
+		for _, order := range []nod.TraversalOrder{nod.BreadthFirst, nod.DepthFirst} {
+			edgeQueries, edgeRows = 0, 0
+			steps, err := repo.Traverse("chain-0").Order(order).MaxDepth(10).FindAll()
+			require.NoError(t, err)
+			require.Len(t, steps, 11)
+			require.Equal(t, int64(1), edgeQueries)
+			require.Equal(t, int64(10), edgeRows)
+
+			edgeQueries, edgeRows = 0, 0
+			steps, err = repo.Traverse("chain-0").Order(order).Limit(3).FindAll()
+			require.NoError(t, err)
+			require.Equal(t, []string{"chain-0", "chain-1", "chain-2"}, traversalNames(steps))
+			require.Equal(t, int64(1), edgeQueries)
+			require.Equal(t, int64(2), edgeRows)
+
+			nodeRows = 0
+			visited := 0
+			err = repo.Traverse("chain-0").Order(order).Each(func(step *nod.TraversalStep) bool {
+				visited++
+				return false
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, visited)
+			require.Less(t, nodeRows, int64(size))
+		}
+	})
+
+	t.Run("fails for a missing start node", func(t *testing.T) {
+		_, err := repo.Traverse("missing").FindAll()
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func traversalNames(steps []*nod.TraversalStep) []string {
+	names := make([]string, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.Node.Core.Name)
+	}
+	return names
+}
+
+func traversalDepths(steps []*nod.TraversalStep) []int {
+	depths := make([]int, 0, len(steps))
+	for _, step := range steps {
+		depths = append(depths, step.Depth)
+	}
+	return depths
+}
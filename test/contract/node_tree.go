@@ -0,0 +1,172 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	treeProjectsID = "tree-projects"
+	treeNodID      = "tree-nod"
+	treeTasksID    = "tree-tasks"
+	treeDocsID     = "tree-docs"
+	treeTaskID     = "tree-task"
+	treeArchiveID  = "tree-archive"
+)
+
+func testNodeTree(t *testing.T, factory RepositoryFactory) {
+	t.Helper()
+
+	t.Run("Children", func(t *testing.T) { testNodeTreeChildren(t, factory) })
+	t.Run("Descendants", func(t *testing.T) { testNodeTreeDescendants(t, factory) })
+	t.Run("Ancestors", func(t *testing.T) { testNodeTreeAncestors(t, factory) })
+	t.Run("Move", func(t *testing.T) { testNodeTreeMove(t, factory) })
+	t.Run("Delete", func(t *testing.T) { testNodeTreeDelete(t, factory) })
+	t.Run("Order", func(t *testing.T) { testNodeTreeOrder(t, factory) })
+	t.Run("Path", func(t *testing.T) { testNodeTreePath(t, factory) })
+	t.Run("Closure", func(t *testing.T) { testNodeTreeClosure(t, factory) })
+	t.Run("Clone", func(t *testing.T) { testNodeTreeClone(t, factory) })
+	t.Run("Typed", func(t *testing.T) { testNodeTreeTyped(t, factory) })
+}
+
+func createTreeTestRepository(t *testing.T, factory RepositoryFactory) *nod.Repository {
+	t.Helper()
+
+	repo := factory(t)
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+
+	for _, node := range []*nod.Node{
+		{Core: nod.NodeCore{Id: treeProjectsID, Name: "projects", Kind: "folder"}},
+		{Core: nod.NodeCore{Id: treeArchiveID, Name: "archive", Kind: "folder"}},
+		{Core: nod.NodeCore{Id: treeNodID, ParentId: nod.Ptr(treeProjectsID), Name: "nod", Kind: "folder"}},
+		{Core: nod.NodeCore{Id: treeTasksID, ParentId: nod.Ptr(treeNodID), Name: "tasks", Kind: "folder"}},
+		{Core: nod.NodeCore{Id: treeDocsID, ParentId: nod.Ptr(treeNodID), Name: "docs", Kind: "folder"}},
+		{
+			Core: nod.NodeCore{Id: treeTaskID, ParentId: nod.Ptr(treeTasksID), Name: "task", Kind: "task", Status: "open"},
+			KV: map[string]*nod.NodeKV{
+				"priority": {Key: "priority", ValueText: nod.Ptr("high")},
+			},
+			Content: map[string]*nod.NodeContent{
+				"body": {Key: "body", Value: nod.Ptr("task body")},
+			},
+			Tags: []*nod.Tag{{Name: "urgent"}},
+		},
+	} {
+		_, err := repo.Nodes().SaveNode(node)
+		require.NoError(t, err)
+	}
+
+	return repo
+}
+
+func treeNodeNames[T any](entries []*nod.TreeNode[T], name func(*T) string) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, name(entry.Model))
+	}
+	return names
+}
+
+func nodeName(node *nod.Node) string {
+	return node.Core.Name
+}
+
+func testNodeTreeChildren(t *testing.T, factory RepositoryFactory) {
+	repo := createTreeTestRepository(t, factory)
+
+	children, err := repo.Nodes().Children(treeNodID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tasks", "docs"}, treeNodeNames(children, nodeName))
+	for _, child := range children {
+		require.Equal(t, 1, child.Depth)
+		require.Equal(t, child.Model.Core.Id, child.Id)
+	}
+
+	children, err = repo.Nodes().Children(treeTaskID)
+	require.NoError(t, err)
+	require.Empty(t, children)
+}
+
+func testNodeTreeDescendants(t *testing.T, factory RepositoryFactory) {
+	repo := createTreeTestRepository(t, factory)
+
+	t.Run("walks the whole subtree", func(t *testing.T) {
+		descendants, err := repo.Nodes().Descendants(treeProjectsID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nod", "tasks", "docs", "task"}, treeNodeNames(descendants, nodeName))
+
+		depths := make([]int, 0, len(descendants))
+		for _, descendant := range descendants {
+			depths = append(depths, descendant.Depth)
+		}
+		require.Equal(t, []int{1, 2, 2, 3}, depths)
+	})
+
+	t.Run("stops at the max depth", func(t *testing.T) {
+		descendants, err := repo.Nodes().Descendants(treeProjectsID, 2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nod", "tasks", "docs"}, treeNodeNames(descendants, nodeName))
+	})
+
+	t.Run("loads relations", func(t *testing.T) {
+		descendants, err := repo.Nodes().Descendants(treeTasksID, 0)
+		require.NoError(t, err)
+		require.Len(t, descendants, 1)
+
+		task := descendants[0].Model
+		require.Equal(t, "high", requireString(t, task.KV["priority"].ValueText))
+		require.Equal(t, "task body", requireString(t, task.Content["body"].Value))
+		require.Equal(t, []string{"urgent"}, tagNames(task.Tags))
+	})
+}
+
+func testNodeTreeAncestors(t *testing.T, factory RepositoryFactory) {
+	repo := createTreeTestRepository(t, factory)
+
+	ancestors, err := repo.Nodes().Ancestors(treeTaskID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tasks", "nod", "projects"}, treeNodeNames(ancestors, nodeName))
+	require.Equal(t, 1, ancestors[0].Depth)
+	require.Equal(t, 3, ancestors[2].Depth)
+
+	ancestors, err = repo.Nodes().Ancestors(treeArchiveID)
+	require.NoError(t, err)
+	require.Empty(t, ancestors)
+}
+
+func testNodeTreeTyped(t *testing.T, factory RepositoryFactory) {
+	repo := factory(t)
+	defer func() { require.NoError(t, repo.Close()) }()
+
+	parentID, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Name: "parent", Kind: "folder"}})
+	require.NoError(t, err)
+
+	scope := nod.Nodes[CustomModelWithNodeCodec](repo)
+	childID, err := scope.SaveNode(&CustomModelWithNodeCodec{
+		Name:        "typed-child",
+		Active:      true,
+		Description: "typed description",
+		Labels:      []string{"typed"},
+		Key:         "typed-key",
+	})
+	require.NoError(t, err)
+
+	child, err := repo.Nodes().GetNode(childID)
+	require.NoError(t, err)
+	child.Core.ParentId = &parentID
+	_, err = repo.Nodes().SaveNode(child)
+	require.NoError(t, err)
+
+	children, err := scope.Children(parentID)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	require.Equal(t, childID, children[0].Id)
+	require.Equal(t, "typed-child", children[0].Model.Name)
+	require.Equal(t, "typed description", children[0].Model.Description)
+	require.Equal(t, []string{"typed"}, children[0].Model.Labels)
+	require.Equal(t, "typed-key", children[0].Model.Key)
+}
@@ -0,0 +1,120 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testNodeTreeClone(t *testing.T, factory RepositoryFactory) {
+	t.Run("copies the subtree with its relations", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := repo.Nodes().CloneSubtree(treeNodID, nod.Ptr(treeArchiveID), nod.CloneOptions{})
+		require.NoError(t, err)
+		require.Len(t, result.NodeIds, 4)
+		require.NotEqual(t, treeNodID, result.RootId)
+		require.Equal(t, result.RootId, result.NodeIds[treeNodID])
+
+		descendants, err := repo.Nodes().Descendants(treeArchiveID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nod", "tasks", "docs", "task"}, treeNodeNames(descendants, nodeName))
+		for _, descendant := range descendants {
+			require.NotContains(t, []string{treeNodID, treeTasksID, treeDocsID, treeTaskID}, descendant.Id)
+		}
+
+		task := descendants[3].Model
+		require.Equal(t, result.NodeIds[treeTaskID], task.Core.Id)
+		require.Equal(t, result.NodeIds[treeTasksID], *task.Core.ParentId)
+		require.Equal(t, "high", requireString(t, task.KV["priority"].ValueText))
+		require.Equal(t, "task body", requireString(t, task.Content["body"].Value))
+		require.Equal(t, []string{"urgent"}, tagNames(task.Tags))
+
+		original, err := repo.Nodes().Descendants(treeProjectsID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nod", "tasks", "docs", "task"}, treeNodeNames(original, nodeName))
+	})
+
+	t.Run("remaps internal edges only", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		weight := 2
+		internalId, err := repo.Edges().SaveEdge(&nod.Edge{
+			Core: nod.EdgeCore{SourceId: treeDocsID, TargetId: treeTaskID, Kind: "describes"},
+			KV:   map[string]*nod.EdgeKV{"weight": {Key: "weight", ValueInt: &weight}},
+			Tags: []*nod.Tag{{Name: "docs"}},
+		})
+		require.NoError(t, err)
+		_, err = repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: treeTaskID, TargetId: treeArchiveID, Kind: "link"}})
+		require.NoError(t, err)
+
+		result, err := repo.Nodes().CloneSubtree(treeNodID, nil, nod.CloneOptions{Name: "nod copy"})
+		require.NoError(t, err)
+		require.Len(t, result.EdgeIds, 1)
+
+		edge, err := repo.Edges().GetEdge(result.EdgeIds[internalId])
+		require.NoError(t, err)
+		require.Equal(t, result.NodeIds[treeDocsID], edge.Core.SourceId)
+		require.Equal(t, result.NodeIds[treeTaskID], edge.Core.TargetId)
+		require.Equal(t, "describes", edge.Core.Kind)
+		require.Equal(t, 2, *edge.KV["weight"].ValueInt)
+		require.Equal(t, []string{"docs"}, tagNames(edge.Tags))
+
+		edges, err := nod.NewEdgeQuery(repo).Where(nod.EdgeFields.SourceId.Equals(result.NodeIds[treeTaskID])).FindAll()
+		require.NoError(t, err)
+		require.Empty(t, edges)
+
+		root, err := repo.Nodes().GetNode(result.RootId)
+		require.NoError(t, err)
+		require.Equal(t, "nod copy", root.Core.Name)
+		require.Nil(t, root.Core.ParentId)
+	})
+
+	t.Run("copies into another namespace", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := repo.Nodes().CloneSubtree(treeTasksID, nil, nod.CloneOptions{NamespaceId: nod.Ptr("templates")})
+		require.NoError(t, err)
+
+		nodes, err := nod.NewNodeQuery(repo).WithTags().Where(nod.NodeFields.NamespaceId.Equals("templates")).FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "tasks", "task")
+
+		task, err := repo.Nodes().GetNode(result.NodeIds[treeTaskID])
+		require.NoError(t, err)
+		require.Equal(t, []string{"urgent"}, tagNames(task.Tags))
+		require.Equal(t, "templates", *task.Tags[0].NamespaceId)
+	})
+
+	t.Run("places the copy after existing children", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		_, err := repo.Nodes().CloneSubtree(treeTasksID, nod.Ptr(treeNodID), nod.CloneOptions{Name: "tasks copy"})
+		require.NoError(t, err)
+
+		children, err := repo.Nodes().Children(treeNodID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tasks", "docs", "tasks copy"}, treeNodeNames(children, nodeName))
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		require.NoError(t, repo.Migrate(nod.WithUniqueSiblingNames()))
+
+		_, err := repo.Nodes().CloneSubtree(treeTasksID, nod.Ptr(treeNodID), nod.CloneOptions{})
+		var duplicateErr *nod.DuplicateNodeNameError
+		require.ErrorAs(t, err, &duplicateErr)
+
+		nodes, err := nod.NewNodeQuery(repo).FindAll()
+		require.NoError(t, err)
+		require.Len(t, nodes, 6)
+	})
+
+	t.Run("fails for a missing root", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		_, err := repo.Nodes().CloneSubtree("missing", nil, nod.CloneOptions{})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
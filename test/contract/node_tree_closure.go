@@ -0,0 +1,131 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testNodeTreeClosure(t *testing.T, factory RepositoryFactory) {
+	createClosureRepository := func(t *testing.T) *nod.Repository {
+		t.Helper()
+		repo := createTreeTestRepository(t, factory)
+		require.NoError(t, repo.Migrate(nod.WithClosureTable()))
+		return repo
+	}
+
+	t.Run("is filled from existing nodes", func(t *testing.T) {
+		repo := createClosureRepository(t)
+
+		var count int64
+		require.NoError(t, repo.DB().Model(&nod.NodeClosure{}).Count(&count).Error)
+		// Six self links, four below projects, three below nod and one below tasks.
+		require.Equal(t, int64(14), count)
+		requireClosureInSync(t, repo)
+	})
+
+	t.Run("answers hierarchy lookups", func(t *testing.T) {
+		repo := createClosureRepository(t)
+
+		descendants, err := repo.Nodes().Descendants(treeProjectsID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nod", "tasks", "docs", "task"}, treeNodeNames(descendants, nodeName))
+		require.Equal(t, 3, descendants[3].Depth)
+		require.Equal(t, "high", requireString(t, descendants[3].Model.KV["priority"].ValueText))
+
+		descendants, err = repo.Nodes().Descendants(treeProjectsID, 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nod"}, treeNodeNames(descendants, nodeName))
+
+		ancestors, err := repo.Nodes().Ancestors(treeTaskID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tasks", "nod", "projects"}, treeNodeNames(ancestors, nodeName))
+
+		path, err := repo.Nodes().PathOf(treeTaskID)
+		require.NoError(t, err)
+		require.Equal(t, "/projects/nod/tasks/task", path)
+
+		nodes, err := nod.NewNodeQuery(repo).Where(nod.NodeFields.Parent.DescendantOf(treeNodID, 0)).FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "tasks", "docs", "task")
+
+		nodes, err = nod.NewNodeQuery(repo).Where(nod.NodeFields.Parent.AncestorOf(treeTaskID)).FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "projects", "nod", "tasks")
+	})
+
+	t.Run("follows saves and moves", func(t *testing.T) {
+		repo := createClosureRepository(t)
+
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Id: "closure-note", ParentId: nod.Ptr(treeDocsID), Name: "note", Kind: "note"}})
+		require.NoError(t, err)
+		requireClosureInSync(t, repo)
+
+		require.NoError(t, repo.Nodes().Move(treeNodID, nod.Ptr(treeArchiveID)))
+		requireClosureInSync(t, repo)
+
+		ancestors, err := repo.Nodes().Ancestors("closure-note")
+		require.NoError(t, err)
+		require.Equal(t, []string{"docs", "nod", "archive"}, treeNodeNames(ancestors, nodeName))
+		path, err := repo.Nodes().PathOf("closure-note")
+		require.NoError(t, err)
+		require.Equal(t, "/archive/nod/docs/note", path)
+
+		require.NoError(t, repo.Nodes().InsertBefore(treeDocsID, treeProjectsID))
+		requireClosureInSync(t, repo)
+
+		_, err = repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Id: treeTasksID, ParentId: nod.Ptr(treeDocsID), Name: "tasks", Kind: "folder"}})
+		require.NoError(t, err)
+		requireClosureInSync(t, repo)
+
+		descendants, err := repo.Nodes().Descendants(treeDocsID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"note", "tasks", "task"}, treeNodeNames(descendants, nodeName))
+	})
+
+	t.Run("rejects cycles", func(t *testing.T) {
+		repo := createClosureRepository(t)
+
+		err := repo.Nodes().Move(treeProjectsID, nod.Ptr(treeTaskID))
+		var cycleErr *nod.ParentCycleError
+		require.ErrorAs(t, err, &cycleErr)
+		requireClosureInSync(t, repo)
+	})
+
+	t.Run("follows deletes", func(t *testing.T) {
+		for _, policy := range []nod.DeletePolicy{nod.DeletePolicyOrphan, nod.DeletePolicyCascade, nod.DeletePolicyReparent} {
+			repo := createClosureRepository(t)
+
+			_, err := repo.Nodes().DeleteNodeWithPolicy(&nod.Node{Core: nod.NodeCore{Id: treeNodID}}, policy)
+			require.NoError(t, err)
+			requireClosureInSync(t, repo)
+		}
+	})
+
+	t.Run("rebuild repairs the table", func(t *testing.T) {
+		repo := createClosureRepository(t)
+		require.NoError(t, repo.DB().Model(&nod.NodeCore{}).Where("id = ?", treeTasksID).Update("parent_id", treeArchiveID).Error)
+
+		require.NoError(t, nod.RebuildNodeClosure(repo.DB()))
+
+		ancestors, err := repo.Nodes().Ancestors(treeTaskID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tasks", "archive"}, treeNodeNames(ancestors, nodeName))
+	})
+}
+
+// requireClosureInSync checks that the maintained closure rows equal the rows
+// rebuilt from parent references.
+func requireClosureInSync(t *testing.T, repo *nod.Repository) {
+	t.Helper()
+
+	var maintained []nod.NodeClosure
+	require.NoError(t, repo.DB().Order("ancestor_id").Order("descendant_id").Find(&maintained).Error)
+
+	require.NoError(t, nod.RebuildNodeClosure(repo.DB()))
+
+	var rebuilt []nod.NodeClosure
+	require.NoError(t, repo.DB().Order("ancestor_id").Order("descendant_id").Find(&rebuilt).Error)
+	require.Equal(t, rebuilt, maintained)
+}
@@ -0,0 +1,194 @@
+package contract
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testNodeTreeDelete(t *testing.T, factory RepositoryFactory) {
+	deleteNod := func(t *testing.T, repo *nod.Repository, policy nod.DeletePolicy) (*nod.DeleteResult, error) {
+		t.Helper()
+		return repo.Nodes().DeleteNodeWithPolicy(&nod.Node{Core: nod.NodeCore{Id: treeNodID}}, policy)
+	}
+
+	requireParent := func(t *testing.T, repo *nod.Repository, id string, parentId *string) {
+		t.Helper()
+		node, err := repo.Nodes().GetNode(id)
+		require.NoError(t, err)
+		require.Equal(t, parentId, node.Core.ParentId)
+	}
+
+	t.Run("orphan turns children into roots", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := deleteNod(t, repo, nod.DeletePolicyOrphan)
+		require.NoError(t, err)
+		require.Equal(t, &nod.DeleteResult{Nodes: 1}, result)
+
+		requireParent(t, repo, treeTasksID, nil)
+		requireParent(t, repo, treeDocsID, nil)
+	})
+
+	t.Run("cascade deletes the subtree", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		_, err := repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: treeTaskID, TargetId: treeArchiveID, Kind: "link"}})
+		require.NoError(t, err)
+		_, err = repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: treeDocsID, TargetId: treeTaskID, Kind: "link"}})
+		require.NoError(t, err)
+
+		result, err := deleteNod(t, repo, nod.DeletePolicyCascade)
+		require.NoError(t, err)
+		require.Equal(t, &nod.DeleteResult{Nodes: 4, Edges: 2}, result)
+
+		for _, id := range []string{treeNodID, treeTasksID, treeDocsID, treeTaskID} {
+			_, err := repo.Nodes().GetNode(id)
+			require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		}
+		remaining, err := nod.NewNodeQuery(repo).FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, remaining, "projects", "archive")
+	})
+
+	t.Run("reparent moves children to the grandparent", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := deleteNod(t, repo, nod.DeletePolicyReparent)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.Nodes)
+
+		requireParent(t, repo, treeTasksID, nod.Ptr(treeProjectsID))
+		requireParent(t, repo, treeDocsID, nod.Ptr(treeProjectsID))
+		requireParent(t, repo, treeTaskID, nod.Ptr(treeTasksID))
+	})
+
+	t.Run("reparent skips ancestors deleted together", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.In([]string{treeNodID, treeTasksID})).
+			DeleteAllWithPolicy(nod.DeletePolicyReparent)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Nodes)
+
+		requireParent(t, repo, treeDocsID, nod.Ptr(treeProjectsID))
+		requireParent(t, repo, treeTaskID, nod.Ptr(treeProjectsID))
+	})
+
+	t.Run("restrict refuses nodes with children", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := deleteNod(t, repo, nod.DeletePolicyRestrict)
+
+		var childrenErr *nod.NodeHasChildrenError
+		require.ErrorAs(t, err, &childrenErr)
+		require.Equal(t, treeNodID, childrenErr.NodeId)
+		require.Nil(t, result)
+		requireParent(t, repo, treeTasksID, nod.Ptr(treeNodID))
+	})
+
+	t.Run("restrict allows deleting a subtree as a whole", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.In([]string{treeTasksID, treeTaskID})).
+			DeleteAllWithPolicy(nod.DeletePolicyRestrict)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Nodes)
+	})
+
+	t.Run("cascade from a query counts shared nodes once", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		result, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Kind.Equals("folder")).
+			DeleteAllWithPolicy(nod.DeletePolicyCascade)
+		require.NoError(t, err)
+		require.Equal(t, int64(6), result.Nodes)
+	})
+
+	t.Run("reparent ends on cycles among deleted nodes", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		for _, core := range []nod.NodeCore{
+			{Id: "cycle-a", Name: "a", Kind: "folder"},
+			{Id: "cycle-b", ParentId: nod.Ptr("cycle-a"), Name: "b", Kind: "folder"},
+			{Id: "cycle-c", ParentId: nod.Ptr("cycle-b"), Name: "c", Kind: "item"},
+		} {
+			_, err := repo.Nodes().SaveNode(&nod.Node{Core: core})
+			require.NoError(t, err)
+		}
+		require.NoError(t, repo.DB().Model(&nod.NodeCore{}).Where("id = ?", "cycle-a").Update("parent_id", "cycle-b").Error)
+
+		result, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.In([]string{"cycle-a", "cycle-b"})).
+			DeleteAllWithPolicy(nod.DeletePolicyReparent)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Nodes)
+
+		requireParent(t, repo, "cycle-c", nil)
+	})
+
+	t.Run("reparent ranks new root nodes in their own namespace", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		for _, core := range []nod.NodeCore{
+			{Id: "ns-b-first", Name: "first", Kind: "folder", NamespaceId: nod.Ptr("ns-b")},
+			{Id: "ns-b-second", Name: "second", Kind: "folder", NamespaceId: nod.Ptr("ns-b")},
+			{Id: "ns-a-root", Name: "root", Kind: "folder", NamespaceId: nod.Ptr("ns-a")},
+			{Id: "ns-a-child", ParentId: nod.Ptr("ns-a-root"), Name: "child", Kind: "item", NamespaceId: nod.Ptr("ns-b")},
+		} {
+			_, err := repo.Nodes().SaveNode(&nod.Node{Core: core})
+			require.NoError(t, err)
+		}
+
+		_, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.Equals("ns-a-root")).
+			DeleteAllWithPolicy(nod.DeletePolicyReparent)
+		require.NoError(t, err)
+
+		requireParent(t, repo, "ns-a-child", nil)
+		second, err := repo.Nodes().GetNode("ns-b-second")
+		require.NoError(t, err)
+		child, err := repo.Nodes().GetNode("ns-a-child")
+		require.NoError(t, err)
+		require.Greater(t, child.Core.Rank, second.Core.Rank)
+	})
+
+	t.Run("cascade deletes subtrees larger than a batch", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		const size = 900
+		cores := []*nod.NodeCore{{Id: "batch-root", Name: "root", Kind: "folder"}}
+		var edges []*nod.EdgeCore
+		for i := range size {
+			id := fmt.Sprintf("batch-%d", i)
+			cores = append(cores, &nod.NodeCore{Id: id, ParentId: nod.Ptr("batch-root"), Name: id, Kind: "item"})
+			if i > 0 {
+				edges = append(edges, &nod.EdgeCore{Id: "batch-edge-" + id, SourceId: fmt.Sprintf("batch-%d", i-1), TargetId: id, Kind: "next"})
+			}
+		}
+		require.NoError(t, repo.DB().CreateInBatches(cores, 100).Error)
+		require.NoError(t, repo.DB().CreateInBatches(edges, 100).Error)
+
+		result, err := repo.Nodes().DeleteNodeWithPolicy(&nod.Node{Core: nod.NodeCore{Id: "batch-root"}}, nod.DeletePolicyCascade)
+		require.NoError(t, err)
+		require.Equal(t, &nod.DeleteResult{Nodes: size + 1, Edges: size - 1}, result)
+
+		remaining, err := nod.NewNodeQuery(repo).FindAll()
+		require.NoError(t, err)
+		require.Empty(t, remaining)
+	})
+
+	t.Run("rejects unknown policies", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		_, err := deleteNod(t, repo, nod.DeletePolicy(42))
+
+		var policyErr *nod.UnsupportedDeletePolicyError
+		require.ErrorAs(t, err, &policyErr)
+	})
+}
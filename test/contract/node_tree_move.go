@@ -0,0 +1,98 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testNodeTreeMove(t *testing.T, factory RepositoryFactory) {
+	t.Run("moves a node under a new parent", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		require.NoError(t, repo.Nodes().Move(treeTasksID, nod.Ptr(treeArchiveID)))
+
+		moved, err := repo.Nodes().GetNode(treeTasksID)
+		require.NoError(t, err)
+		require.Equal(t, treeArchiveID, *moved.Core.ParentId)
+
+		descendants, err := repo.Nodes().Descendants(treeArchiveID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tasks", "task"}, treeNodeNames(descendants, nodeName))
+	})
+
+	t.Run("keeps relations of the moved node", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		require.NoError(t, repo.Nodes().Move(treeTaskID, nod.Ptr(treeDocsID)))
+
+		moved, err := repo.Nodes().GetNode(treeTaskID)
+		require.NoError(t, err)
+		require.Equal(t, treeDocsID, *moved.Core.ParentId)
+		require.Equal(t, "high", requireString(t, moved.KV["priority"].ValueText))
+		require.Equal(t, "task body", requireString(t, moved.Content["body"].Value))
+		require.Equal(t, []string{"urgent"}, tagNames(moved.Tags))
+	})
+
+	t.Run("moves a node to the root", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		require.NoError(t, repo.Nodes().Move(treeNodID, nil))
+
+		moved, err := repo.Nodes().GetNode(treeNodID)
+		require.NoError(t, err)
+		require.Nil(t, moved.Core.ParentId)
+	})
+
+	t.Run("rejects moving a node under itself", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		err := repo.Nodes().Move(treeNodID, nod.Ptr(treeNodID))
+
+		var cycleErr *nod.ParentCycleError
+		require.ErrorAs(t, err, &cycleErr)
+		require.Equal(t, treeNodID, cycleErr.NodeId)
+	})
+
+	t.Run("rejects moving a node under its descendant", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		err := repo.Nodes().Move(treeProjectsID, nod.Ptr(treeTaskID))
+
+		var cycleErr *nod.ParentCycleError
+		require.ErrorAs(t, err, &cycleErr)
+		require.Equal(t, treeProjectsID, cycleErr.NodeId)
+		require.Equal(t, treeTaskID, cycleErr.ParentId)
+
+		unchanged, err := repo.Nodes().GetNode(treeProjectsID)
+		require.NoError(t, err)
+		require.Nil(t, unchanged.Core.ParentId)
+	})
+
+	t.Run("returns record not found for a missing node", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		err := repo.Nodes().Move("missing", nod.Ptr(treeArchiveID))
+
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("save rejects cycles", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		node, err := repo.Nodes().GetNode(treeNodID)
+		require.NoError(t, err)
+		node.Core.ParentId = nod.Ptr(treeTasksID)
+
+		_, err = repo.Nodes().SaveNode(node)
+
+		var cycleErr *nod.ParentCycleError
+		require.ErrorAs(t, err, &cycleErr)
+
+		unchanged, err := repo.Nodes().GetNode(treeNodID)
+		require.NoError(t, err)
+		require.Equal(t, treeProjectsID, *unchanged.Core.ParentId)
+	})
+}
@@ -0,0 +1,189 @@
+package contract
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testNodeTreeOrder(t *testing.T, factory RepositoryFactory) {
+	childNames := func(t *testing.T, repo *nod.Repository, parentId string) []string {
+		t.Helper()
+		children, err := repo.Nodes().Children(parentId)
+		require.NoError(t, err)
+		return treeNodeNames(children, nodeName)
+	}
+
+	ranks := func(t *testing.T, repo *nod.Repository, parentId string) map[string]string {
+		t.Helper()
+		children, err := repo.Nodes().Children(parentId)
+		require.NoError(t, err)
+		result := make(map[string]string, len(children))
+		for _, child := range children {
+			result[child.Model.Core.Name] = child.Model.Core.Rank
+		}
+		return result
+	}
+
+	createList := func(t *testing.T, repo *nod.Repository, size int) string {
+		t.Helper()
+		listID, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Name: "list", Kind: "folder"}})
+		require.NoError(t, err)
+		for i := range size {
+			_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{
+				Id:       fmt.Sprintf("item-%d", i),
+				ParentId: &listID,
+				Name:     fmt.Sprintf("item-%d", i),
+				Kind:     "item",
+			}})
+			require.NoError(t, err)
+		}
+		return listID
+	}
+
+	t.Run("children keep insertion order", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		require.Equal(t, []string{"tasks", "docs"}, childNames(t, repo, treeNodID))
+	})
+
+	t.Run("saving a node keeps its rank", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		before := ranks(t, repo, treeNodID)
+
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{
+			Id:       treeTasksID,
+			ParentId: nod.Ptr(treeNodID),
+			Name:     "tasks",
+			Kind:     "folder",
+		}})
+		require.NoError(t, err)
+
+		require.Equal(t, before, ranks(t, repo, treeNodID))
+	})
+
+	t.Run("insert before renumbers only the moved node", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		before := ranks(t, repo, treeNodID)
+
+		require.NoError(t, repo.Nodes().InsertBefore(treeDocsID, treeTasksID))
+
+		require.Equal(t, []string{"docs", "tasks"}, childNames(t, repo, treeNodID))
+		after := ranks(t, repo, treeNodID)
+		require.Equal(t, before["tasks"], after["tasks"])
+	})
+
+	t.Run("insert after moves the node to the sibling's parent", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		require.NoError(t, repo.Nodes().InsertAfter(treeArchiveID, treeTasksID))
+
+		require.Equal(t, []string{"tasks", "archive", "docs"}, childNames(t, repo, treeNodID))
+		archive, err := repo.Nodes().GetNode(treeArchiveID)
+		require.NoError(t, err)
+		require.Equal(t, treeNodID, *archive.Core.ParentId)
+	})
+
+	t.Run("inserting a node next to itself keeps its place", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		listID := createList(t, repo, 3)
+		before := ranks(t, repo, listID)
+
+		require.NoError(t, repo.Nodes().InsertBefore("item-0", "item-0"))
+		require.NoError(t, repo.Nodes().InsertAfter("item-1", "item-1"))
+
+		require.Equal(t, []string{"item-0", "item-1", "item-2"}, childNames(t, repo, listID))
+		require.Equal(t, before, ranks(t, repo, listID))
+		require.ErrorIs(t, repo.Nodes().InsertAfter("missing", "missing"), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("insert rejects cycles", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		err := repo.Nodes().InsertBefore(treeNodID, treeTaskID)
+
+		var cycleErr *nod.ParentCycleError
+		require.ErrorAs(t, err, &cycleErr)
+	})
+
+	t.Run("insert rejects siblings in another namespace", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		for _, core := range []nod.NodeCore{
+			{Id: "root-a", Name: "root-a", Kind: "list", NamespaceId: nod.Ptr("ns-a")},
+			{Id: "root-b", Name: "root-b", Kind: "list", NamespaceId: nod.Ptr("ns-b")},
+		} {
+			_, err := repo.Nodes().SaveNode(&nod.Node{Core: core})
+			require.NoError(t, err)
+		}
+		before, err := repo.Nodes().GetNode("root-a")
+		require.NoError(t, err)
+
+		err = repo.Nodes().InsertAfter("root-a", "root-b")
+		var namespaceErr *nod.NamespaceMismatchError
+		require.ErrorAs(t, err, &namespaceErr)
+		require.Equal(t, "root-a", namespaceErr.NodeId)
+		require.Equal(t, "root-b", namespaceErr.SiblingId)
+
+		after, err := repo.Nodes().GetNode("root-a")
+		require.NoError(t, err)
+		require.Equal(t, before.Core.Rank, after.Core.Rank)
+	})
+
+	t.Run("move to index", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		listID := createList(t, repo, 5)
+		before := ranks(t, repo, listID)
+
+		require.NoError(t, repo.Nodes().MoveToIndex("item-4", 0))
+		require.Equal(t, []string{"item-4", "item-0", "item-1", "item-2", "item-3"}, childNames(t, repo, listID))
+
+		require.NoError(t, repo.Nodes().MoveToIndex("item-0", 2))
+		require.Equal(t, []string{"item-4", "item-1", "item-0", "item-2", "item-3"}, childNames(t, repo, listID))
+
+		require.NoError(t, repo.Nodes().MoveToIndex("item-4", 100))
+		require.Equal(t, []string{"item-1", "item-0", "item-2", "item-3", "item-4"}, childNames(t, repo, listID))
+
+		after := ranks(t, repo, listID)
+		for _, name := range []string{"item-1", "item-2", "item-3"} {
+			require.Equal(t, before[name], after[name])
+		}
+	})
+
+	t.Run("move appends after the new siblings", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		require.NoError(t, repo.Nodes().Move(treeArchiveID, nod.Ptr(treeNodID)))
+
+		require.Equal(t, []string{"tasks", "docs", "archive"}, childNames(t, repo, treeNodID))
+	})
+
+	t.Run("ranks siblings stored without one", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+		listID := createList(t, repo, 3)
+		require.NoError(t, repo.DB().Model(&nod.NodeCore{}).Where("parent_id = ?", listID).Update("rank", "").Error)
+
+		require.NoError(t, repo.Nodes().MoveToIndex("item-0", 3))
+
+		require.Equal(t, []string{"item-1", "item-2", "item-0"}, childNames(t, repo, listID))
+		for _, rank := range ranks(t, repo, listID) {
+			require.NotEmpty(t, rank)
+		}
+	})
+
+	t.Run("rejects invalid ranks", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Name: "invalid", Kind: "test", Rank: "A0"}})
+
+		var rankErr *nod.InvalidRankError
+		require.ErrorAs(t, err, &rankErr)
+	})
+}
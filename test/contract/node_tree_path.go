@@ -0,0 +1,154 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testNodeTreePath(t *testing.T, factory RepositoryFactory) {
+	t.Run("resolves a path", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		node, err := repo.Nodes().ResolvePath(nil, "/projects/nod/tasks/task")
+		require.NoError(t, err)
+		require.Equal(t, treeTaskID, node.Core.Id)
+		require.Equal(t, "high", requireString(t, node.KV["priority"].ValueText))
+
+		node, err = repo.Nodes().ResolvePath(nil, "projects/nod/")
+		require.NoError(t, err)
+		require.Equal(t, treeNodID, node.Core.Id)
+	})
+
+	t.Run("resolves a path within a namespace", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+
+		for _, node := range []*nod.Node{
+			{Core: nod.NodeCore{Id: "path-root-a", NamespaceId: nod.Ptr("a"), Name: "root", Kind: "folder"}},
+			{Core: nod.NodeCore{Id: "path-root-b", NamespaceId: nod.Ptr("b"), Name: "root", Kind: "folder"}},
+			{Core: nod.NodeCore{Id: "path-child-b", NamespaceId: nod.Ptr("b"), ParentId: nod.Ptr("path-root-b"), Name: "child", Kind: "folder"}},
+		} {
+			_, err := repo.Nodes().SaveNode(node)
+			require.NoError(t, err)
+		}
+
+		node, err := repo.Nodes().ResolvePath(nod.Ptr("b"), "/root/child")
+		require.NoError(t, err)
+		require.Equal(t, "path-child-b", node.Core.Id)
+
+		_, err = repo.Nodes().ResolvePath(nod.Ptr("a"), "/root/child")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = repo.Nodes().ResolvePath(nil, "/root")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("resolves typed models", func(t *testing.T) {
+		repo := factory(t)
+		defer func() { require.NoError(t, repo.Close()) }()
+
+		scope := nod.Nodes[CustomModelWithNodeCodec](repo)
+		_, err := scope.SaveNode(&CustomModelWithNodeCodec{Name: "typed", Description: "typed description", Key: "typed-key"})
+		require.NoError(t, err)
+
+		model, err := scope.ResolvePath(nil, "/typed")
+		require.NoError(t, err)
+		require.Equal(t, "typed description", model.Description)
+	})
+
+	t.Run("reports missing and ambiguous paths", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		_, err := repo.Nodes().ResolvePath(nil, "/projects/missing")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{ParentId: nod.Ptr(treeNodID), Name: "docs", Kind: "folder"}})
+		require.NoError(t, err)
+		_, err = repo.Nodes().ResolvePath(nil, "/projects/nod/docs")
+		var multipleErr *nod.MultipleNodesFoundError
+		require.ErrorAs(t, err, &multipleErr)
+
+		_, err = repo.Nodes().ResolvePath(nil, "/")
+		var pathErr *nod.InvalidPathError
+		require.ErrorAs(t, err, &pathErr)
+	})
+
+	t.Run("path of a node", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+
+		path, err := repo.Nodes().PathOf(treeTaskID)
+		require.NoError(t, err)
+		require.Equal(t, "/projects/nod/tasks/task", path)
+
+		path, err = repo.Nodes().PathOf(treeArchiveID)
+		require.NoError(t, err)
+		require.Equal(t, "/archive", path)
+
+		_, err = repo.Nodes().PathOf("missing")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("unique sibling names", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		require.NoError(t, repo.Migrate(nod.WithUniqueSiblingNames()))
+
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{ParentId: nod.Ptr(treeNodID), Name: "docs", Kind: "folder"}})
+		var duplicateErr *nod.DuplicateNodeNameError
+		require.ErrorAs(t, err, &duplicateErr)
+		require.Equal(t, "docs", duplicateErr.Name)
+
+		_, err = repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Name: "projects", Kind: "folder"}})
+		require.ErrorAs(t, err, &duplicateErr)
+
+		err = repo.Nodes().Move(treeDocsID, nod.Ptr(treeTasksID))
+		require.NoError(t, err)
+		err = repo.Nodes().InsertAfter(treeDocsID, treeTasksID)
+		require.NoError(t, err)
+
+		_, err = repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Id: "docs-copy", ParentId: nod.Ptr(treeArchiveID), Name: "docs", Kind: "folder"}})
+		require.NoError(t, err)
+		err = repo.Nodes().Move("docs-copy", nod.Ptr(treeNodID))
+		require.ErrorAs(t, err, &duplicateErr)
+
+		_, err = repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{NamespaceId: nod.Ptr("other"), Name: "projects", Kind: "folder"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("unique sibling names reject existing duplicates", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Name: "projects", Kind: "folder"}})
+		require.NoError(t, err)
+
+		require.Error(t, repo.Migrate(nod.WithUniqueSiblingNames()))
+	})
+
+	t.Run("unique sibling names keep the separator out of names", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		require.NoError(t, repo.Migrate(nod.WithUniqueSiblingNames()))
+
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{ParentId: nod.Ptr(treeNodID), Name: "a/b", Kind: "folder"}})
+		var nameErr *nod.InvalidNodeNameError
+		require.ErrorAs(t, err, &nameErr)
+		require.Equal(t, "a/b", nameErr.Name)
+
+		for _, id := range []string{treeTaskID, treeDocsID, treeArchiveID} {
+			path, err := repo.Nodes().PathOf(id)
+			require.NoError(t, err)
+			node, err := repo.Nodes().ResolvePath(nil, path)
+			require.NoError(t, err)
+			require.Equal(t, id, node.Core.Id)
+		}
+	})
+
+	t.Run("unique sibling names reject existing names with the separator", func(t *testing.T) {
+		repo := createTreeTestRepository(t, factory)
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Name: "a/b", Kind: "folder"}})
+		require.NoError(t, err)
+
+		var nameErr *nod.InvalidNodeNameError
+		require.ErrorAs(t, repo.Migrate(nod.WithUniqueSiblingNames()), &nameErr)
+	})
+}
@@ -10,6 +10,14 @@ func testQueries(t *testing.T, factory RepositoryFactory) {
 	t.Run("Tags", func(t *testing.T) { testQueryTags(t, factory) })
 	t.Run("Content", func(t *testing.T) { testQueryContent(t, factory) })
 	t.Run("KV", func(t *testing.T) { testQueryKV(t, factory) })
+	t.Run("StringPatterns", func(t *testing.T) { testQueryStringPatterns(t, factory) })
+	t.Run("Presence", func(t *testing.T) { testQueryPresence(t, factory) })
+	t.Run("Timestamps", func(t *testing.T) { testQueryTimestamps(t, factory) })
+	t.Run("Order", func(t *testing.T) { testQueryOrder(t, factory) })
+	t.Run("Aggregate", func(t *testing.T) { testQueryAggregate(t, factory) })
+	t.Run("Hierarchy", func(t *testing.T) { testQueryHierarchy(t, factory) })
+	t.Run("Edges", func(t *testing.T) { testQueryEdges(t, factory) })
+	t.Run("Preload", func(t *testing.T) { testQueryPreload(t, factory) })
 	t.Run("MixedParameters", func(t *testing.T) { testQueryMixedParameters(t, factory) })
 	t.Run("LogicalOperators", func(t *testing.T) { testQueryLogicalOperators(t, factory) })
 	t.Run("MultipleWhere", func(t *testing.T) { testQueryMultipleWhere(t, factory) })
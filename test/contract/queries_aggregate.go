@@ -0,0 +1,150 @@
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryAggregate(t *testing.T, factory RepositoryFactory) {
+	repo := createOrderTestRepository(t, factory)
+
+	t.Run("counts matching nodes", func(t *testing.T) {
+		count, err := nod.NewNodeQuery(repo).Count()
+		require.NoError(t, err)
+		require.EqualValues(t, 5, count)
+
+		count, err = nod.NewNodeQuery(repo).
+			Where(nod.KvInt("priority").GreaterThan(1)).
+			Limit(1).
+			Count()
+		require.NoError(t, err)
+		require.EqualValues(t, 3, count)
+	})
+
+	t.Run("reports whether any node matches", func(t *testing.T) {
+		exists, err := nod.NewNodeQuery(repo).Where(nod.NodeFields.Name.Equals("gamma")).Exists()
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		exists, err = nod.NewNodeQuery(repo).Where(nod.NodeFields.Name.Equals("missing")).Exists()
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("aggregates numeric kv values", func(t *testing.T) {
+		query := nod.NewNodeQuery(repo).Where(nod.NodeFields.Kind.Equals("task"))
+
+		sum, err := nod.Sum(query, nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Equal(t, 8, sum)
+
+		avg, err := query.Avg(nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Equal(t, 2.0, *avg)
+
+		minimum, err := nod.Min(query, nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Equal(t, 1, *minimum)
+
+		maximum, err := nod.Max(query, nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Equal(t, 3, *maximum)
+	})
+
+	t.Run("aggregates only matching nodes", func(t *testing.T) {
+		maximum, err := nod.Max(
+			nod.NewNodeQuery(repo).Where(nod.NodeFields.Name.In([]string{"alpha", "beta"})),
+			nod.KvInt("priority"),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, 2, *maximum)
+	})
+
+	t.Run("aggregates nodes without values", func(t *testing.T) {
+		query := nod.NewNodeQuery(repo).Where(nod.NodeFields.Name.Equals("delta"))
+
+		sum, err := nod.Sum(query, nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Zero(t, sum)
+
+		maximum, err := nod.Max(query, nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Nil(t, maximum)
+
+		avg, err := query.Avg(nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Nil(t, avg)
+	})
+
+	t.Run("groups by a kv value", func(t *testing.T) {
+		groups, err := nod.NewNodeQuery(repo).GroupBy(nod.KvInt("priority"))
+
+		require.NoError(t, err)
+		require.Equal(t, []nod.GroupCount{
+			{Value: int64(1), Count: 1},
+			{Value: int64(2), Count: 2},
+			{Value: int64(3), Count: 1},
+			{Value: nil, Count: 1},
+		}, groups)
+	})
+
+	t.Run("groups by a core field", func(t *testing.T) {
+		groups, err := nod.NewNodeQuery(repo).
+			Where(nod.KvInt("priority").Exists()).
+			GroupBy(nod.NodeFields.Kind)
+
+		require.NoError(t, err)
+		require.Equal(t, []nod.GroupCount{{Value: "task", Count: 4}}, groups)
+	})
+
+	t.Run("groups by a timestamp", func(t *testing.T) {
+		groups, err := nod.NewNodeQuery(repo).GroupBy(nod.NodeFields.CreatedAt)
+
+		require.NoError(t, err)
+		require.Len(t, groups, 5)
+		first, ok := groups[0].Value.(time.Time)
+		require.True(t, ok)
+		require.True(t, first.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("typed queries forward counts and aggregates", func(t *testing.T) {
+		query := nod.NewTypedNodeQuery[nod.Node](repo).Where(nod.KvInt("priority").Exists())
+
+		count, err := query.Count()
+		require.NoError(t, err)
+		require.EqualValues(t, 4, count)
+
+		sum, err := nod.Sum(query, nod.KvInt("priority"))
+		require.NoError(t, err)
+		require.Equal(t, 8, sum)
+	})
+
+	t.Run("aggregates int64 values beyond float precision", func(t *testing.T) {
+		const large = int64(1<<53 + 1)
+		for i, name := range []string{"large-a", "large-b"} {
+			value := large + int64(i)*2
+			_, err := repo.Nodes().SaveNode(&nod.Node{
+				Core: nod.NodeCore{Name: name, Kind: "large"},
+				KV:   map[string]*nod.NodeKV{"size": {Key: "size", ValueInt64: &value}},
+			})
+			require.NoError(t, err)
+		}
+		query := nod.NewNodeQuery(repo).Where(nod.NodeFields.Kind.Equals("large"))
+
+		sum, err := nod.Sum(query, nod.KvInt64("size"))
+		require.NoError(t, err)
+		require.Equal(t, 2*large+2, sum)
+
+		minimum, err := nod.Min(query, nod.KvInt64("size"))
+		require.NoError(t, err)
+		require.Equal(t, large, *minimum)
+
+		maximum, err := nod.Max(query, nod.KvInt64("size"))
+		require.NoError(t, err)
+		require.Equal(t, large+2, *maximum)
+	})
+}
@@ -0,0 +1,74 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryEdges(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+	_, err := repo.Nodes().SaveNode(&nod.Node{
+		Core: nod.NodeCore{Id: "graph-person", Name: "person", Kind: "person"},
+		Tags: []*nod.Tag{{Name: "team-a"}},
+	})
+	require.NoError(t, err)
+	_, err = repo.Edges().SaveEdge(&nod.Edge{Core: nod.EdgeCore{SourceId: graphBID, TargetId: "graph-person", Kind: "assigned_to"}})
+	require.NoError(t, err)
+
+	find := func(t *testing.T, expr nod.Expression, expected ...string) {
+		t.Helper()
+		nodes, err := nod.NewNodeQuery(repo).Where(expr).FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, expected...)
+	}
+
+	t.Run("outgoing edges", func(t *testing.T) {
+		find(t, nod.NodeFields.Edges.Outgoing("blocks", nil), "d")
+		find(t, nod.NodeFields.Edges.Outgoing("depends_on", nil), "a", "b", "c", "d", "f")
+		find(t, nod.NodeFields.Edges.Outgoing("depends_on", nod.NodeFields.Id.Equals(graphDID)), "b", "c")
+		find(t, nod.NodeFields.Edges.Outgoing("assigned_to", nod.Tags().Has("team-a")), "b")
+		find(t, nod.Or(
+			nod.NodeFields.Kind.Equals("person"),
+			nod.NodeFields.Edges.Outgoing("assigned_to", nod.Tags().Has("team-b")),
+		), "person")
+		find(t, nod.NodeFields.Edges.Outgoing("", nod.KvString("title").Equals("task e")), "d")
+	})
+
+	t.Run("incoming edges", func(t *testing.T) {
+		find(t, nod.NodeFields.Edges.Incoming("", nil), "a", "b", "c", "d", "e", "person")
+		find(t, nod.NodeFields.Edges.Incoming("depends_on", nod.NodeFields.Name.In([]string{"f"})), "a")
+	})
+
+	t.Run("edge counts", func(t *testing.T) {
+		find(t, nod.NodeFields.Edges.IncomingCount("", nil).Equals(0), "f", "g")
+		find(t, nod.NodeFields.Edges.OutgoingCount("depends_on", nil).GreaterThanOrEqual(2), "a")
+		find(t, nod.NodeFields.Edges.OutgoingCount("", nil).GreaterThan(1), "a", "b", "d")
+		find(t, nod.NodeFields.Edges.IncomingCount("depends_on", nod.NodeFields.Name.Equals("d")).LessThan(1), "b", "c", "d", "e", "f", "g", "person")
+	})
+
+	t.Run("combines with other expressions", func(t *testing.T) {
+		find(t, nod.And(
+			nod.NodeFields.Edges.Incoming("depends_on", nil),
+			nod.NodeFields.Edges.Outgoing("blocks", nil),
+		), "d")
+		find(t, nod.Or(
+			nod.NodeFields.Kind.Equals("person"),
+			nod.NodeFields.Edges.Outgoing("depends_on", nod.NodeFields.Edges.Outgoing("blocks", nil)),
+		), "b", "c", "person")
+	})
+
+	t.Run("nil node expressions match any node", func(t *testing.T) {
+		var anyNode nod.Expression
+		find(t, nod.NodeFields.Edges.Outgoing("blocks", anyNode), "d")
+		find(t, nod.Not(nod.NodeFields.Edges.Outgoing("depends_on", nil)), "e", "g", "person")
+		find(t, nod.NodeFields.Edges.IncomingCount("assigned_to", anyNode).Equals(1), "person")
+	})
+
+	t.Run("is not supported in edge queries", func(t *testing.T) {
+		_, err := nod.NewEdgeQuery(repo).Where(nod.NodeFields.Edges.Outgoing("", nil)).FindAll()
+		var scopeErr *nod.UnsupportedScopeError
+		require.ErrorAs(t, err, &scopeErr)
+	})
+}
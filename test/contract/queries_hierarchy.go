@@ -0,0 +1,86 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryHierarchy(t *testing.T, factory RepositoryFactory) {
+	repo := createTreeTestRepository(t, factory)
+
+	tests := []struct {
+		name       string
+		expression nod.Expression
+		expected   []string
+	}{
+		{
+			name:       "child of",
+			expression: nod.NodeFields.Parent.ChildOf(treeNodID),
+			expected:   []string{"docs", "tasks"},
+		},
+		{
+			name:       "descendant of",
+			expression: nod.NodeFields.Parent.DescendantOf(treeProjectsID, 0),
+			expected:   []string{"nod", "docs", "tasks", "task"},
+		},
+		{
+			name:       "descendant of with max depth",
+			expression: nod.NodeFields.Parent.DescendantOf(treeProjectsID, 1),
+			expected:   []string{"nod"},
+		},
+		{
+			name:       "ancestor of",
+			expression: nod.NodeFields.Parent.AncestorOf(treeTaskID),
+			expected:   []string{"tasks", "nod", "projects"},
+		},
+		{
+			name:       "has any child",
+			expression: nod.NodeFields.Parent.HasChildMatching(nil),
+			expected:   []string{"projects", "nod", "tasks"},
+		},
+		{
+			name:       "has child matching",
+			expression: nod.NodeFields.Parent.HasChildMatching(nod.KvString("priority").Equals("high")),
+			expected:   []string{"tasks"},
+		},
+		{
+			name: "open tasks anywhere under a project",
+			expression: nod.And(
+				nod.NodeFields.Parent.DescendantOf(treeProjectsID, 0),
+				nod.NodeFields.Kind.Equals("task"),
+				nod.NodeFields.Status.Equals("open"),
+			),
+			expected: []string{"task"},
+		},
+		{
+			name: "combines with or",
+			expression: nod.Or(
+				nod.NodeFields.Parent.AncestorOf(treeDocsID),
+				nod.NodeFields.Name.Equals("archive"),
+			),
+			expected: []string{"nod", "projects", "archive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := nod.NewNodeQuery(repo).
+				Where(tt.expression).
+				FindAll()
+
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, tt.expected...)
+		})
+	}
+
+	t.Run("rejects hierarchy expressions on edges", func(t *testing.T) {
+		_, err := nod.NewEdgeQuery(repo).
+			Where(nod.NodeFields.Parent.DescendantOf(treeProjectsID, 0)).
+			FindAll()
+
+		var scopeErr *nod.UnsupportedScopeError
+		require.ErrorAs(t, err, &scopeErr)
+	})
+}
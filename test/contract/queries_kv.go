@@ -76,5 +76,66 @@ func testQueryKV(t *testing.T, factory RepositoryFactory) {
 
 		require.NoError(t, err)
 		requireQueryNodeNames(t, nodes, "before")
+
+		nodes, err = nod.NewNodeQuery(repo).
+			Where(nod.KvTime("start").Between(before, cutoff)).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "before")
+
+		nodes, err = nod.NewNodeQuery(repo).
+			Where(nod.KvTime("start").NotEquals(before)).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "after")
+
+		nodes, err = nod.NewNodeQuery(repo).
+			Where(nod.KvTime("start").In([]time.Time{before, after})).
+			FindAll()
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "after", "before")
+	})
+
+	t.Run("compares numeric and boolean values", func(t *testing.T) {
+		for i, name := range []string{"small", "medium", "large"} {
+			priority := i + 1
+			views := int64(i) * 1_000_000_000_000
+			score := 0.5 + float64(i)
+			pinned := i == 1
+			_, err := repo.Nodes().SaveNode(&nod.Node{
+				Core: nod.NodeCore{Id: "query-number-" + name, Name: name, Kind: "metric"},
+				KV: map[string]*nod.NodeKV{
+					"priority": {Key: "priority", ValueInt: &priority},
+					"views":    {Key: "views", ValueInt64: &views},
+					"score":    {Key: "score", ValueNumber: &score},
+					"pinned":   {Key: "pinned", ValueBool: &pinned},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		find := func(t *testing.T, expr nod.Expression, expected ...string) {
+			t.Helper()
+			nodes, err := nod.NewNodeQuery(repo).Where(expr).FindAll()
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, expected...)
+		}
+
+		find(t, nod.KvInt("priority").Equals(2), "medium")
+		find(t, nod.KvInt("priority").NotEquals(2), "small", "large")
+		find(t, nod.KvInt("priority").GreaterThan(1), "medium", "large")
+		find(t, nod.KvInt("priority").LessThanOrEqual(2), "small", "medium")
+		find(t, nod.KvInt("priority").In([]int{1, 3}), "small", "large")
+		find(t, nod.KvInt64("views").GreaterThanOrEqual(1_000_000_000_000), "medium", "large")
+		find(t, nod.KvInt64("views").Between(1, 1_000_000_000_000), "medium")
+		find(t, nod.KvFloat("score").LessThan(1.5), "small")
+		find(t, nod.KvFloat("score").Between(1.5, 2.5), "medium", "large")
+		find(t, nod.KvFloat("score").NotIn([]float64{0.5}), "medium", "large")
+		find(t, nod.KvBool("pinned").Equals(true), "medium")
+		find(t, nod.KvBool("pinned").NotEquals(true), "small", "large")
+
+		nodes, err := nod.NewNodeQuery(repo).Where(nod.KvInt("score").Equals(1)).FindAll()
+		require.NoError(t, err)
+		require.Empty(t, nodes)
 	})
 }
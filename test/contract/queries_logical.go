@@ -70,4 +70,42 @@ func testQueryLogicalOperators(t *testing.T, factory RepositoryFactory) {
 		require.NoError(t, err)
 		requireQueryNodeNames(t, nodes, "beta", "gamma")
 	})
+
+	t.Run("not", func(t *testing.T) {
+		find := func(t *testing.T, expr nod.Expression, expected ...string) {
+			t.Helper()
+			nodes, err := nod.NewNodeQuery(repo).Where(expr).FindAll()
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, expected...)
+		}
+
+		find(t, nod.Not(nod.NodeFields.Kind.Equals("article")), "gamma", "delta")
+		find(t, nod.Not(nod.Or(
+			nod.NodeFields.Status.Equals("published"),
+			nod.NodeFields.Status.Equals("draft"),
+		)), "delta")
+		find(t, nod.Not(nod.Not(nod.NodeFields.Name.Equals("beta"))), "beta")
+		find(t, nod.And(
+			nod.NodeFields.Kind.Equals("article"),
+			nod.Not(nod.Tags().Has("featured")),
+		), "beta")
+	})
+
+	t.Run("not matches missing values", func(t *testing.T) {
+		_, err := repo.Nodes().SaveNode(&nod.Node{Core: nod.NodeCore{Id: "query-node-epsilon", Name: "epsilon", Kind: "note"}})
+		require.NoError(t, err)
+
+		find := func(t *testing.T, expr nod.Expression, expected ...string) {
+			t.Helper()
+			nodes, err := nod.NewNodeQuery(repo).Where(expr).FindAll()
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, expected...)
+		}
+
+		find(t, nod.Not(nod.Tags().Has("shared")), "delta", "epsilon")
+		find(t, nod.Not(nod.KvString("color").Equals("red")), "gamma", "delta", "epsilon")
+		find(t, nod.Not(nod.Content("body").Equals("delta body")), "alpha", "beta", "gamma", "epsilon")
+		find(t, nod.Not(nod.NodeFields.ParentId.Equals(queryNodeAlphaID)), "alpha", "gamma", "delta", "epsilon")
+		find(t, nod.Not(nod.NodeFields.NamespaceId.In([]string{queryNamespaceA})), "gamma", "delta", "epsilon")
+	})
 }
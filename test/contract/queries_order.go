@@ -0,0 +1,303 @@
+package contract
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func createOrderTestRepository(t *testing.T, factory RepositoryFactory) *nod.Repository {
+	t.Helper()
+
+	repo := factory(t)
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	priorities := map[string]int{"alpha": 2, "beta": 1, "gamma": 2, "epsilon": 3}
+	for i, name := range []string{"delta", "alpha", "epsilon", "gamma", "beta"} {
+		node := &nod.Node{
+			Core: nod.NodeCore{
+				Id:        "order-node-" + name,
+				Name:      name,
+				Kind:      "task",
+				CreatedAt: base.Add(time.Duration(i) * time.Hour),
+			},
+			KV: map[string]*nod.NodeKV{},
+		}
+		if priority, ok := priorities[name]; ok {
+			node.KV["priority"] = &nod.NodeKV{Key: "priority", ValueInt: &priority}
+		}
+		_, err := repo.Nodes().SaveNode(node)
+		require.NoError(t, err)
+	}
+	return repo
+}
+
+func queryNodeNamesInOrder(nodes []*nod.Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Core.Name)
+	}
+	return names
+}
+
+func testQueryOrder(t *testing.T, factory RepositoryFactory) {
+	repo := createOrderTestRepository(t, factory)
+
+	tests := []struct {
+		name     string
+		query    func() *nod.NodeQuery
+		expected []string
+	}{
+		{
+			name: "core field ascending",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending)
+			},
+			expected: []string{"alpha", "beta", "delta", "epsilon", "gamma"},
+		},
+		{
+			name: "core field descending",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Descending)
+			},
+			expected: []string{"gamma", "epsilon", "delta", "beta", "alpha"},
+		},
+		{
+			name: "timestamp",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.CreatedAt, nod.Descending)
+			},
+			expected: []string{"beta", "gamma", "epsilon", "alpha", "delta"},
+		},
+		{
+			name: "kv value with missing values last and ties broken by id",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.KvInt("priority"), nod.Ascending)
+			},
+			expected: []string{"beta", "alpha", "gamma", "epsilon", "delta"},
+		},
+		{
+			name: "kv value descending keeps missing values last",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.KvInt("priority"), nod.Descending)
+			},
+			expected: []string{"epsilon", "alpha", "gamma", "beta", "delta"},
+		},
+		{
+			name: "several sort keys",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).
+					OrderBy(nod.KvInt("priority"), nod.Descending).
+					OrderBy(nod.NodeFields.Name, nod.Descending)
+			},
+			expected: []string{"epsilon", "gamma", "alpha", "beta", "delta"},
+		},
+		{
+			name: "limit and offset",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).Limit(2).Offset(1)
+			},
+			expected: []string{"beta", "delta"},
+		},
+		{
+			name: "offset without limit",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).Offset(3)
+			},
+			expected: []string{"epsilon", "gamma"},
+		},
+		{
+			name: "limit with a filter",
+			query: func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).
+					Where(nod.KvInt("priority").Exists()).
+					OrderBy(nod.NodeFields.CreatedAt, nod.Ascending).
+					Limit(3)
+			},
+			expected: []string{"alpha", "epsilon", "gamma"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, err := tc.query().FindAll()
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, queryNodeNamesInOrder(nodes))
+		})
+	}
+
+	t.Run("first node in order", func(t *testing.T) {
+		node, err := nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Descending).FindFirst()
+
+		require.NoError(t, err)
+		require.Equal(t, "gamma", node.Core.Name)
+	})
+
+	t.Run("pages through every node with cursors", func(t *testing.T) {
+		for _, direction := range []nod.SortDirection{nod.Ascending, nod.Descending} {
+			all, err := nod.NewNodeQuery(repo).OrderBy(nod.KvInt("priority"), direction).FindAll()
+			require.NoError(t, err)
+
+			var names []string
+			cursor := ""
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 5)
+				query := nod.NewNodeQuery(repo).OrderBy(nod.KvInt("priority"), direction).Limit(2)
+				if cursor != "" {
+					query.After(cursor)
+				}
+				page, err := query.FindPage()
+				require.NoError(t, err)
+				names = append(names, queryNodeNamesInOrder(page.Items)...)
+				if page.NextCursor == "" {
+					break
+				}
+				cursor = page.NextCursor
+			}
+			require.Equal(t, queryNodeNamesInOrder(all), names)
+		}
+	})
+
+	t.Run("pages by timestamp", func(t *testing.T) {
+		page, err := nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.CreatedAt, nod.Descending).Limit(3).FindPage()
+		require.NoError(t, err)
+		require.Equal(t, []string{"beta", "gamma", "epsilon"}, queryNodeNamesInOrder(page.Items))
+		require.NotEmpty(t, page.NextCursor)
+
+		page, err = nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.CreatedAt, nod.Descending).Limit(3).After(page.NextCursor).FindPage()
+		require.NoError(t, err)
+		require.Equal(t, []string{"alpha", "delta"}, queryNodeNamesInOrder(page.Items))
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("returns every node without a limit", func(t *testing.T) {
+		page, err := nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).FindPage()
+
+		require.NoError(t, err)
+		require.Len(t, page.Items, 5)
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("rejects malformed cursors", func(t *testing.T) {
+		page, err := nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).Limit(1).FindPage()
+		require.NoError(t, err)
+
+		for _, cursor := range []string{"not a cursor!", "e30"} {
+			_, err := nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).After(cursor).FindAll()
+			var cursorErr *nod.InvalidCursorError
+			require.ErrorAs(t, err, &cursorErr)
+		}
+
+		_, err = nod.NewNodeQuery(repo).After(page.NextCursor).FindAll()
+		var cursorErr *nod.InvalidCursorError
+		require.ErrorAs(t, err, &cursorErr)
+	})
+
+	t.Run("pages by kv times, content and several keys", func(t *testing.T) {
+		repo := createOrderTestRepository(t, factory)
+		base := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+		for i, name := range []string{"gamma", "alpha", "delta", "beta"} {
+			due := base.Add(time.Duration(i%2) * time.Hour)
+			node, err := repo.Nodes().GetNode("order-node-" + name)
+			require.NoError(t, err)
+			node.KV["due"] = &nod.NodeKV{Key: "due", ValueTime: &due}
+			node.Content = map[string]*nod.NodeContent{"title": {Key: "title", Value: nod.Ptr(strings.ToUpper(name[:1]) + name[1:])}}
+			_, err = repo.Nodes().SaveNode(node)
+			require.NoError(t, err)
+		}
+
+		queries := []func() *nod.NodeQuery{
+			func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.KvTime("due"), nod.Descending)
+			},
+			func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).OrderBy(nod.Content("title").IgnoreCase(), nod.Ascending)
+			},
+			func() *nod.NodeQuery {
+				return nod.NewNodeQuery(repo).
+					OrderBy(nod.KvTime("due"), nod.Ascending).
+					OrderBy(nod.KvInt("priority"), nod.Descending).
+					OrderBy(nod.NodeFields.CreatedAt, nod.Ascending)
+			},
+		}
+		for _, query := range queries {
+			all, err := query().FindAll()
+			require.NoError(t, err)
+
+			var names []string
+			cursor := ""
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 5)
+				next := query().Limit(2)
+				if cursor != "" {
+					next.After(cursor)
+				}
+				page, err := next.FindPage()
+				require.NoError(t, err)
+				names = append(names, queryNodeNamesInOrder(page.Items)...)
+				if page.NextCursor == "" {
+					break
+				}
+				cursor = page.NextCursor
+			}
+			require.Equal(t, queryNodeNamesInOrder(all), names)
+		}
+	})
+
+	t.Run("reads a page and its cursor without a query per sort key", func(t *testing.T) {
+		var queries int
+		count := func(db *gorm.DB) { queries++ }
+		require.NoError(t, repo.DB().Callback().Query().After("gorm:query").Register("contract:count_page_queries", count))
+		require.NoError(t, repo.DB().Callback().Row().After("gorm:row").Register("contract:count_page_rows", count))
+		defer func() {
+			require.NoError(t, repo.DB().Callback().Query().Remove("contract:count_page_queries"))
+			require.NoError(t, repo.DB().Callback().Row().Remove("contract:count_page_rows"))
+		}()
+
+		page, err := nod.NewNodeQuery(repo).
+			OrderBy(nod.KvInt("priority"), nod.Descending).
+			OrderBy(nod.NodeFields.Name, nod.Ascending).
+			Limit(2).
+			FindPage()
+		require.NoError(t, err)
+		require.Equal(t, []string{"epsilon", "alpha"}, queryNodeNamesInOrder(page.Items))
+		require.NotEmpty(t, page.NextCursor)
+		require.Equal(t, 2, queries)
+	})
+
+	t.Run("rejects an offset with a cursor", func(t *testing.T) {
+		page, err := nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).Limit(2).FindPage()
+		require.NoError(t, err)
+
+		_, err = nod.NewNodeQuery(repo).OrderBy(nod.NodeFields.Name, nod.Ascending).Limit(2).Offset(1).After(page.NextCursor).FindPage()
+		var offsetErr *nod.OffsetWithCursorError
+		require.ErrorAs(t, err, &offsetErr)
+	})
+
+	t.Run("typed queries forward ordering and pages", func(t *testing.T) {
+		page, err := nod.NewTypedNodeQuery[nod.Node](repo).
+			OrderBy(nod.NodeFields.Name, nod.Ascending).
+			Offset(1).
+			Limit(2).
+			FindPage()
+		require.NoError(t, err)
+		require.Equal(t, []string{"beta", "delta"}, queryNodeNamesInOrder(page.Items))
+
+		page, err = nod.NewTypedNodeQuery[nod.Node](repo).
+			OrderBy(nod.NodeFields.Name, nod.Ascending).
+			Limit(2).
+			After(page.NextCursor).
+			FindPage()
+		require.NoError(t, err)
+		require.Equal(t, []string{"epsilon", "gamma"}, queryNodeNamesInOrder(page.Items))
+		require.Empty(t, page.NextCursor)
+	})
+}
@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryPreload(t *testing.T, factory RepositoryFactory) {
+	repo := createGraphTestRepository(t, factory)
+
+	edgeIds := func(edges []*nod.Edge) []string {
+		ids := make([]string, 0, len(edges))
+		for _, edge := range edges {
+			ids = append(ids, edge.Core.Id)
+		}
+		return ids
+	}
+
+	t.Run("edges are not loaded by default", func(t *testing.T) {
+		node, err := nod.NewNodeQuery(repo).Where(nod.NodeFields.Id.Equals(graphDID)).FindFirst()
+		require.NoError(t, err)
+		require.Nil(t, node.Outgoing)
+		require.Nil(t, node.Incoming)
+	})
+
+	t.Run("outgoing and incoming edges", func(t *testing.T) {
+		nodes, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.In([]string{graphAID, graphDID, graphGID})).
+			WithOutgoingEdges("").
+			WithIncomingEdges("depends_on", nod.LoadKV()).
+			FindAll()
+		require.NoError(t, err)
+		require.Len(t, nodes, 3)
+
+		byId := make(map[string]*nod.Node, len(nodes))
+		for _, node := range nodes {
+			byId[node.Core.Id] = node
+		}
+		require.Equal(t, []string{"graph-a-b", "graph-a-c"}, edgeIds(byId[graphAID].Outgoing))
+		require.ElementsMatch(t, []string{"graph-d-a", "graph-f-a"}, edgeIds(byId[graphAID].Incoming))
+		require.Equal(t, []string{"graph-d-a", "graph-d-e"}, edgeIds(byId[graphDID].Outgoing))
+		require.ElementsMatch(t, []string{"graph-b-d", "graph-c-d"}, edgeIds(byId[graphDID].Incoming))
+		require.NotNil(t, byId[graphGID].Outgoing)
+		require.Empty(t, byId[graphGID].Outgoing)
+		require.Empty(t, byId[graphGID].Incoming)
+
+		for _, edge := range byId[graphDID].Incoming {
+			require.NotNil(t, edge.KV["weight"])
+		}
+		require.Nil(t, byId[graphDID].Outgoing[0].KV)
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		node, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.Equals(graphDID)).
+			WithOutgoingEdges("blocks").
+			FindFirst()
+		require.NoError(t, err)
+		require.Equal(t, []string{"graph-d-e"}, edgeIds(node.Outgoing))
+
+		node, err = nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.Id.Equals(graphDID)).
+			WithOutgoingEdges("blocks").
+			WithOutgoingEdges("depends_on").
+			FindFirst()
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"graph-d-a", "graph-d-e"}, edgeIds(node.Outgoing))
+	})
+}
@@ -0,0 +1,109 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryPresence(t *testing.T, factory RepositoryFactory) {
+	repo := createQueryTestRepository(t, factory)
+
+	_, err := repo.Nodes().SaveNode(&nod.Node{
+		Core: nod.NodeCore{Id: "query-node-epsilon", Name: "epsilon", Kind: "note", Status: "draft"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression nod.Expression
+		expected   []string
+	}{
+		{
+			name:       "null parent id",
+			expression: nod.NodeFields.ParentId.IsNull(),
+			expected:   []string{"alpha", "epsilon", "gamma"},
+		},
+		{
+			name:       "not null parent id",
+			expression: nod.NodeFields.ParentId.IsNotNull(),
+			expected:   []string{"beta", "delta"},
+		},
+		{
+			name:       "existing parent id",
+			expression: nod.NodeFields.ParentId.Exists(),
+			expected:   []string{"beta", "delta"},
+		},
+		{
+			name:       "negated null parent id",
+			expression: nod.Not(nod.NodeFields.ParentId.IsNull()),
+			expected:   []string{"beta", "delta"},
+		},
+		{
+			name:       "existing kv key",
+			expression: nod.KvString("accent").Exists(),
+			expected:   []string{"beta"},
+		},
+		{
+			name:       "missing kv key",
+			expression: nod.Not(nod.KvString("accent").Exists()),
+			expected:   []string{"alpha", "delta", "epsilon", "gamma"},
+		},
+		{
+			name:       "kv key exists whatever its type",
+			expression: nod.KvInt("accent").Exists(),
+			expected:   []string{"beta"},
+		},
+		{
+			name:       "kv key without a value in the typed column",
+			expression: nod.KvInt("accent").IsNull(),
+			expected:   []string{"beta"},
+		},
+		{
+			name:       "kv key with a value in the typed column",
+			expression: nod.KvString("accent").IsNotNull(),
+			expected:   []string{"beta"},
+		},
+		{
+			name:       "existing content key",
+			expression: nod.Content("body").Exists(),
+			expected:   []string{"alpha", "beta", "delta", "gamma"},
+		},
+		{
+			name:       "missing content key",
+			expression: nod.Not(nod.Content("body").Exists()),
+			expected:   []string{"epsilon"},
+		},
+		{
+			name:       "any tag",
+			expression: nod.Tags().Any(),
+			expected:   []string{"alpha", "beta", "delta", "gamma"},
+		},
+		{
+			name:       "no tags",
+			expression: nod.Not(nod.Tags().Any()),
+			expected:   []string{"epsilon"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, err := nod.NewNodeQuery(repo).
+				Where(tc.expression).
+				FindAll()
+
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, tc.expected...)
+		})
+	}
+
+	t.Run("kv key without a value in another typed column", func(t *testing.T) {
+		nodes, err := nod.NewNodeQuery(repo).
+			Where(nod.KvInt("accent").IsNotNull()).
+			FindAll()
+
+		require.NoError(t, err)
+		require.Empty(t, nodes)
+	})
+}
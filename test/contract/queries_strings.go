@@ -0,0 +1,181 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryStringPatterns(t *testing.T, factory RepositoryFactory) {
+	repo := createQueryTestRepository(t, factory)
+
+	nodes := []*nod.Node{
+		{
+			Core: nod.NodeCore{Id: "query-node-discount", Name: "50% Off", Kind: "offer"},
+			KV: map[string]*nod.NodeKV{
+				"code": {Key: "code", ValueText: nod.Ptr("SALE_50")},
+			},
+			Content: map[string]*nod.NodeContent{
+				"body": {Key: "body", Value: nod.Ptr(`Save 50% with C:\deals`)},
+			},
+		},
+		{
+			Core: nod.NodeCore{Id: "query-node-percent", Name: "50 percent off", Kind: "offer"},
+			KV: map[string]*nod.NodeKV{
+				"code": {Key: "code", ValueText: nod.Ptr("SALEX50")},
+			},
+			Content: map[string]*nod.NodeContent{
+				"body": {Key: "body", Value: nod.Ptr("Save 50 percent with deals")},
+			},
+		},
+		{
+			Core: nod.NodeCore{Id: "query-node-glob", Name: "glob [*?] chars", Kind: "offer"},
+		},
+	}
+	for _, node := range nodes {
+		_, err := repo.Nodes().SaveNode(node)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name       string
+		expression nod.Expression
+		expected   []string
+	}{
+		{
+			name:       "like with wildcards",
+			expression: nod.NodeFields.Name.Like("_e%a"),
+			expected:   []string{"beta", "delta"},
+		},
+		{
+			name:       "like with an escaped wildcard",
+			expression: nod.NodeFields.Name.Like(`50\%%`),
+			expected:   []string{"50% Off"},
+		},
+		{
+			name:       "prefix",
+			expression: nod.NodeFields.Name.Prefix("al"),
+			expected:   []string{"alpha"},
+		},
+		{
+			name:       "prefix matches wildcards literally",
+			expression: nod.NodeFields.Name.Prefix("50%"),
+			expected:   []string{"50% Off"},
+		},
+		{
+			name:       "suffix",
+			expression: nod.NodeFields.Name.Suffix("ta"),
+			expected:   []string{"beta", "delta"},
+		},
+		{
+			name:       "contains",
+			expression: nod.NodeFields.Name.Contains("mm"),
+			expected:   []string{"gamma"},
+		},
+		{
+			name:       "contains matches underscores literally",
+			expression: nod.KvString("code").Contains("E_5"),
+			expected:   []string{"50% Off"},
+		},
+		{
+			name:       "kv prefix",
+			expression: nod.KvString("color").Prefix("gr"),
+			expected:   []string{"delta"},
+		},
+		{
+			name:       "content contains",
+			expression: nod.Content("body").Contains("a bo"),
+			expected:   []string{"alpha", "beta", "delta", "gamma"},
+		},
+		{
+			name:       "content contains a backslash",
+			expression: nod.Content("body").Contains(`C:\deals`),
+			expected:   []string{"50% Off"},
+		},
+		{
+			name:       "contains matches glob wildcards literally",
+			expression: nod.NodeFields.Name.Contains("[*?]"),
+			expected:   []string{"glob [*?] chars"},
+		},
+		{
+			name:       "like matches glob wildcards literally",
+			expression: nod.NodeFields.Name.Like("%*%"),
+			expected:   []string{"glob [*?] chars"},
+		},
+		{
+			name:       "case-insensitive prefix matches another case",
+			expression: nod.NodeFields.Name.IgnoreCase().Prefix("Al"),
+			expected:   []string{"alpha"},
+		},
+		{
+			name:       "case-insensitive equality",
+			expression: nod.NodeFields.Name.IgnoreCase().Equals("ALPHA"),
+			expected:   []string{"alpha"},
+		},
+		{
+			name:       "case-insensitive in",
+			expression: nod.NodeFields.Name.IgnoreCase().In([]string{"Beta", "GAMMA"}),
+			expected:   []string{"beta", "gamma"},
+		},
+		{
+			name:       "case-insensitive prefix",
+			expression: nod.NodeFields.Name.IgnoreCase().Prefix("50% OFF"),
+			expected:   []string{"50% Off"},
+		},
+		{
+			name:       "case-insensitive kv equality",
+			expression: nod.KvString("code").IgnoreCase().Equals("salex50"),
+			expected:   []string{"50 percent off"},
+		},
+		{
+			name:       "case-insensitive content suffix",
+			expression: nod.Content("body").IgnoreCase().Suffix("WITH DEALS"),
+			expected:   []string{"50 percent off"},
+		},
+		{
+			name:       "negated contains",
+			expression: nod.Not(nod.NodeFields.Name.Contains("a")),
+			expected:   []string{"50 percent off", "50% Off"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, err := nod.NewNodeQuery(repo).
+				Where(tc.expression).
+				FindAll()
+
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, tc.expected...)
+		})
+	}
+
+	t.Run("ignoring case leaves the original field case-sensitive", func(t *testing.T) {
+		name := nod.NodeFields.Name
+		_ = name.IgnoreCase()
+
+		nodes, err := nod.NewNodeQuery(repo).
+			Where(name.Equals("ALPHA")).
+			FindAll()
+
+		require.NoError(t, err)
+		require.Empty(t, nodes)
+	})
+
+	t.Run("patterns are case-sensitive without IgnoreCase", func(t *testing.T) {
+		for _, expression := range []nod.Expression{
+			nod.NodeFields.Name.Prefix("Al"),
+			nod.NodeFields.Name.Like("AL%"),
+			nod.Content("body").Contains("ALPHA"),
+			nod.KvString("color").Suffix("ED"),
+		} {
+			nodes, err := nod.NewNodeQuery(repo).
+				Where(expression).
+				FindAll()
+
+			require.NoError(t, err)
+			require.Empty(t, nodes)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m87/nod"
+	"github.com/stretchr/testify/require"
+)
+
+func testQueryTimestamps(t *testing.T, factory RepositoryFactory) {
+	repo := factory(t)
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ids := make(map[string]string)
+	for i, name := range []string{"first", "second", "third"} {
+		id, err := repo.Nodes().SaveNode(&nod.Node{
+			Core: nod.NodeCore{Name: name, Kind: "note", CreatedAt: base.Add(time.Duration(i) * time.Hour)},
+		})
+		require.NoError(t, err)
+		ids[name] = id
+	}
+
+	tests := []struct {
+		name       string
+		expression nod.Expression
+		expected   []string
+	}{
+		{
+			name:       "equals",
+			expression: nod.NodeFields.CreatedAt.Equals(base),
+			expected:   []string{"first"},
+		},
+		{
+			name:       "not equals",
+			expression: nod.NodeFields.CreatedAt.NotEquals(base),
+			expected:   []string{"second", "third"},
+		},
+		{
+			name:       "greater than",
+			expression: nod.NodeFields.CreatedAt.GreaterThan(base.Add(time.Hour)),
+			expected:   []string{"third"},
+		},
+		{
+			name:       "greater than or equal",
+			expression: nod.NodeFields.CreatedAt.GreaterThanOrEqual(base.Add(time.Hour)),
+			expected:   []string{"second", "third"},
+		},
+		{
+			name:       "less than",
+			expression: nod.NodeFields.CreatedAt.LessThan(base.Add(time.Hour)),
+			expected:   []string{"first"},
+		},
+		{
+			name:       "less than or equal",
+			expression: nod.NodeFields.CreatedAt.LessThanOrEqual(base.Add(time.Hour)),
+			expected:   []string{"first", "second"},
+		},
+		{
+			name:       "between",
+			expression: nod.NodeFields.CreatedAt.Between(base.Add(30*time.Minute), base.Add(2*time.Hour)),
+			expected:   []string{"second", "third"},
+		},
+		{
+			name:       "in",
+			expression: nod.NodeFields.CreatedAt.In([]time.Time{base, base.Add(2 * time.Hour)}),
+			expected:   []string{"first", "third"},
+		},
+		{
+			name:       "not in",
+			expression: nod.NodeFields.CreatedAt.NotIn([]time.Time{base, base.Add(2 * time.Hour)}),
+			expected:   []string{"second"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, err := nod.NewNodeQuery(repo).
+				Where(tc.expression).
+				FindAll()
+
+			require.NoError(t, err)
+			requireQueryNodeNames(t, nodes, tc.expected...)
+		})
+	}
+
+	t.Run("finds nodes updated since a point in time", func(t *testing.T) {
+		since := time.Now()
+		_, err := repo.Nodes().SaveNode(&nod.Node{
+			Core: nod.NodeCore{Id: ids["second"], Name: "second", Kind: "note", Status: "done", CreatedAt: base.Add(time.Hour)},
+		})
+		require.NoError(t, err)
+
+		nodes, err := nod.NewNodeQuery(repo).
+			Where(nod.NodeFields.UpdatedAt.GreaterThan(since)).
+			FindAll()
+
+		require.NoError(t, err)
+		requireQueryNodeNames(t, nodes, "second")
+	})
+}
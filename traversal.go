@@ -0,0 +1,31 @@
+package nod
+
+// Direction selects which edges of a node a graph walk follows.
+type Direction uint8
+
+const (
+	// DirectionOutgoing follows edges from their source to their target.
+	DirectionOutgoing Direction = iota
+	// DirectionIncoming follows edges from their target back to their source.
+	DirectionIncoming
+	// DirectionBoth follows edges in either direction.
+	DirectionBoth
+)
+
+// TraversalOrder selects the order in which a traversal visits nodes.
+type TraversalOrder uint8
+
+const (
+	// BreadthFirst visits all nodes at one depth before going deeper.
+	BreadthFirst TraversalOrder = iota
+	// DepthFirst follows each path as deep as possible before backtracking.
+	DepthFirst
+)
+
+// TraversalStep is a node visited by a traversal together with the edge that
+// reached it. The start node is visited first, at depth 0 and without an edge.
+type TraversalStep struct {
+	Node  *Node
+	Edge  *Edge
+	Depth int
+}
@@ -0,0 +1,8 @@
+package nod
+
+// TreeNode pairs a model found by a hierarchy lookup with its distance from the node the lookup started at.
+type TreeNode[T any] struct {
+	Id    string
+	Depth int
+	Model *T
+}
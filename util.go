@@ -3,3 +3,17 @@ package nod
 func Ptr(str string) *string {
 	return &str
 }
+
+// uniqueStrings returns values without duplicates, in order of first
+// occurrence.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}